@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/koinos/koinos-log-golang/v2"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+	flag "github.com/spf13/pflag"
+)
+
+const (
+	exportCommand    = "export"
+	importCommand    = "import"
+	verifyCommand    = "verify"
+	rotateKeyCommand = "rotate-key"
+)
+
+const (
+	formatOption           = "format"
+	includeAuxiliaryOption = "include-auxiliary"
+)
+
+const (
+	formatDefault = "protobuf"
+)
+
+// runCommand runs a maintenance command against the transaction store instead of starting the service
+func runCommand(trxStore *trxstore.TransactionStore, args []string) error {
+	switch args[0] {
+	case exportCommand:
+		return runExport(trxStore, args[1:])
+	case importCommand:
+		return runImport(trxStore, args[1:])
+	case verifyCommand:
+		return runVerify(trxStore, args[1:])
+	}
+
+	return fmt.Errorf("unknown command '%s'", args[0])
+}
+
+func runExport(trxStore *trxstore.TransactionStore, args []string) error {
+	flags := flag.NewFlagSet(exportCommand, flag.ContinueOnError)
+	format := flags.StringP(formatOption, "f", formatDefault, "The export format (protobuf, json)")
+	includeAuxiliary := flags.Bool(includeAuxiliaryOption, false, "Also export auxiliary records such as receipts and indexes, which can only be imported into an empty store")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] %s [flags] <file>\n", os.Args[0], exportCommand)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected a single output file")
+	}
+
+	exportFormat, err := trxstore.ParseExportFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	log.Infof("Exporting transactions to %s", flags.Arg(0))
+	count, err := trxStore.Export(file, exportFormat, *includeAuxiliary)
+	if err != nil {
+		return fmt.Errorf("export failed after %v record(s): %w", count, err)
+	}
+
+	log.Infof("Exported %v record(s)", count)
+	return file.Sync()
+}
+
+func runImport(trxStore *trxstore.TransactionStore, args []string) error {
+	flags := flag.NewFlagSet(importCommand, flag.ContinueOnError)
+	format := flags.StringP(formatOption, "f", formatDefault, "The import format (protobuf, json)")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] %s [flags] <file>\n", os.Args[0], importCommand)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected a single input file")
+	}
+
+	importFormat, err := trxstore.ParseExportFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	log.Infof("Importing transactions from %s", flags.Arg(0))
+	count, err := trxStore.Import(file, importFormat)
+	if err != nil {
+		return fmt.Errorf("import failed after %v record(s): %w", count, err)
+	}
+
+	log.Infof("Imported %v record(s)", count)
+	return nil
+}
+
+func runVerify(trxStore *trxstore.TransactionStore, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%s does not take any arguments", verifyCommand)
+	}
+
+	log.Info("Verifying database")
+	result, err := trxStore.Verify(func(problem *trxstore.VerificationProblem) {
+		log.Warn(problem.String())
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Verified %v transaction(s) and %v auxiliary record(s)", result.Transactions, result.AuxiliaryRecords)
+	if result.Problems > 0 {
+		return fmt.Errorf("found %v problem(s)", result.Problems)
+	}
+
+	log.Info("No problems found")
+	return nil
+}
+
+// runRotateKey encrypts the database in dbDir, currently encrypted with key, with the key
+// in the file given in args. The database must not be open.
+func runRotateKey(dbDir string, key []byte, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s takes the new key file as its only argument", rotateKeyCommand)
+	}
+
+	newKey, err := trxstore.ReadEncryptionKey(args[0])
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Rotating the encryption key of %s", dbDir)
+	if err := trxstore.RotateBadgerEncryptionKey(dbDir, key, newKey); err != nil {
+		return err
+	}
+
+	log.Infof("Rotated the encryption key, set %s to %s before restarting", encryptionKeyOption, args[0])
+	return nil
+}
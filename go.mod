@@ -3,12 +3,20 @@ module github.com/koinos/koinos-transaction-store
 go 1.16
 
 require (
+	github.com/btcsuite/btcd v0.20.1-beta
+	github.com/btcsuite/btcutil v1.0.2
 	github.com/dgraph-io/badger/v3 v3.2103.2
+	github.com/golang/snappy v0.0.4
+	github.com/klauspost/compress v1.15.9
 	github.com/koinos/koinos-log-golang/v2 v2.0.0
 	github.com/koinos/koinos-mq-golang v1.0.1
 	github.com/koinos/koinos-proto-golang/v2 v2.0.2
 	github.com/koinos/koinos-util-golang/v2 v2.0.1
+	github.com/multiformats/go-multihash v0.1.0
 	github.com/spf13/pflag v1.0.3
 	go.uber.org/zap v1.17.0
+	golang.org/x/net v0.8.0
+	google.golang.org/grpc v1.55.0
 	google.golang.org/protobuf v1.30.0
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,226 @@
+package diskguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/koinos/koinos-log-golang/v2"
+)
+
+const defaultInterval = 30 * time.Second
+
+// ErrUnsupported occurs when free disk space cannot be read on this platform
+var ErrUnsupported = errors.New("free disk space is not supported on this platform")
+
+// Thresholds configures when disk usage is too high. A threshold of 0 is not checked.
+type Thresholds struct {
+	// MinFreeBytes is the least free space the file system holding the database may have
+	MinFreeBytes uint64
+
+	// MaxDatabaseBytes is the largest the database directory may grow
+	MaxDatabaseBytes uint64
+}
+
+// Status is the disk usage found by the last check
+type Status struct {
+	DatabaseBytes uint64    `json:"database_bytes"`
+	FreeBytes     uint64    `json:"free_bytes"`
+	TotalBytes    uint64    `json:"total_bytes"`
+	Exceeded      bool      `json:"exceeded"`
+	Reason        string    `json:"reason,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// Option configures optional Guard behavior
+type Option func(*Guard)
+
+// WithInterval sets the time between checks
+func WithInterval(interval time.Duration) Option {
+	return func(g *Guard) {
+		g.interval = interval
+	}
+}
+
+// WithCheckHandler sets a function called with the status found by each check
+func WithCheckHandler(handler func(Status)) Option {
+	return func(g *Guard) {
+		g.onCheck = handler
+	}
+}
+
+// withStatDisk replaces the function free disk space is read with
+func withStatDisk(stat func(path string) (uint64, uint64, error)) Option {
+	return func(g *Guard) {
+		g.statDisk = stat
+	}
+}
+
+// Guard periodically checks the size of the database directory and the free
+// space of its file system against thresholds. While a threshold is exceeded it
+// logs an error on every check, reports itself unhealthy, and holds back writers
+// waiting for space.
+type Guard struct {
+	dir        string
+	thresholds Thresholds
+	interval   time.Duration
+	statDisk   func(path string) (uint64, uint64, error)
+	onCheck    func(Status)
+
+	mutex  sync.Mutex
+	status Status
+
+	// recovered is closed, and replaced, when a threshold is no longer exceeded
+	recovered chan struct{}
+}
+
+// NewGuard creates a Guard checking the database in the given directory
+func NewGuard(dir string, thresholds Thresholds, opts ...Option) *Guard {
+	g := &Guard{
+		dir:        dir,
+		thresholds: thresholds,
+		interval:   defaultInterval,
+		statDisk:   statDisk,
+		recovered:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// Run checks disk usage every interval until the context is done
+func (g *Guard) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			g.Check()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check measures disk usage, updates the status and returns it
+func (g *Guard) Check() Status {
+	status := Status{CheckedAt: time.Now()}
+
+	var errs []string
+	size, err := directorySize(g.dir)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	status.DatabaseBytes = size
+
+	status.FreeBytes, status.TotalBytes, err = g.statDisk(g.dir)
+	if err != nil && !errors.Is(err, ErrUnsupported) {
+		errs = append(errs, err.Error())
+	}
+
+	if g.thresholds.MinFreeBytes > 0 && status.TotalBytes > 0 && status.FreeBytes < g.thresholds.MinFreeBytes {
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("%v byte(s) free is below the minimum of %v", status.FreeBytes, g.thresholds.MinFreeBytes)
+	} else if g.thresholds.MaxDatabaseBytes > 0 && status.DatabaseBytes > g.thresholds.MaxDatabaseBytes {
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("database size of %v byte(s) is above the maximum of %v", status.DatabaseBytes, g.thresholds.MaxDatabaseBytes)
+	}
+	if len(errs) > 0 {
+		status.Error = fmt.Sprint(errs)
+		log.Warnf("Could not measure disk usage: %s", status.Error)
+	}
+
+	g.mutex.Lock()
+	wasExceeded := g.status.Exceeded
+	g.status = status
+	if wasExceeded && !status.Exceeded {
+		close(g.recovered)
+		g.recovered = make(chan struct{})
+	}
+	g.mutex.Unlock()
+
+	if status.Exceeded {
+		log.Errorf("Disk usage threshold exceeded, %s", status.Reason)
+	} else if wasExceeded {
+		log.Infof("Disk usage is back within thresholds")
+	}
+
+	if g.onCheck != nil {
+		g.onCheck(status)
+	}
+
+	return status
+}
+
+// Status returns the status found by the last check
+func (g *Guard) Status() Status {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	return g.status
+}
+
+// WaitForSpace blocks while a threshold is exceeded, until a check finds it is
+// not or the context is done. Returns the context's error if it is done first.
+func (g *Guard) WaitForSpace(ctx context.Context) error {
+	for {
+		g.mutex.Lock()
+		exceeded := g.status.Exceeded
+		recovered := g.recovered
+		g.mutex.Unlock()
+
+		if !exceeded {
+			return nil
+		}
+
+		select {
+		case <-recovered:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ServeHTTP reports the status as JSON, with a 503 status code while a threshold is exceeded
+func (g *Guard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status := g.Status()
+
+	w.Header().Set("Content-Type", "application/json")
+	if status.Exceeded {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Warnf("Could not write health status: %s", err.Error())
+	}
+}
+
+// directorySize returns the total size of the regular files in a directory tree
+func directorySize(dir string) (uint64, error) {
+	size := uint64(0)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		// Files may be removed by compaction while they are walked
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,130 @@
+package diskguard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDisk reports the free space it is set to
+type fakeDisk struct {
+	mutex sync.Mutex
+	free  uint64
+}
+
+func (d *fakeDisk) setFree(free uint64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.free = free
+}
+
+func (d *fakeDisk) stat(path string) (uint64, uint64, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.free, 1000, nil
+}
+
+func TestGuard(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), "diskguard-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "000001.vlog"), make([]byte, 100), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	disk := &fakeDisk{free: 500}
+	var checked []Status
+	guard := NewGuard(dir, Thresholds{MinFreeBytes: 200, MaxDatabaseBytes: 150}, withStatDisk(disk.stat), WithCheckHandler(func(status Status) {
+		checked = append(checked, status)
+	}))
+
+	status := guard.Check()
+	if status.Exceeded || status.DatabaseBytes != 100 || status.FreeBytes != 500 || status.TotalBytes != 1000 {
+		t.Fatalf("Unexpected status %+v", status)
+	}
+	if err := guard.WaitForSpace(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(checked) != 1 || checked[0] != status {
+		t.Fatalf("Expected the check handler to be called with %+v, got %+v", status, checked)
+	}
+
+	// Too little free space
+	disk.setFree(100)
+	if status := guard.Check(); !status.Exceeded {
+		t.Fatalf("Expected the free space threshold to be exceeded, got %+v", status)
+	}
+
+	recorder := httptest.NewRecorder()
+	guard.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status code %v, got %v", http.StatusServiceUnavailable, recorder.Code)
+	}
+	reported := &Status{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), reported); err != nil {
+		t.Fatal(err)
+	}
+	if !reported.Exceeded || reported.FreeBytes != 100 {
+		t.Fatalf("Unexpected reported status %+v", reported)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := guard.WaitForSpace(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	// Writers waiting for space resume once it has been freed
+	done := make(chan error)
+	go func() {
+		done <- guard.WaitForSpace(context.Background())
+	}()
+
+	disk.setFree(500)
+	guard.Check()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the writer to resume")
+	}
+
+	recorder = httptest.NewRecorder()
+	guard.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status code %v, got %v", http.StatusOK, recorder.Code)
+	}
+
+	// A database that has grown too large
+	if err := os.WriteFile(filepath.Join(dir, "000002.vlog"), make([]byte, 100), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if status := guard.Check(); !status.Exceeded || status.DatabaseBytes != 200 {
+		t.Fatalf("Expected the database size threshold to be exceeded, got %+v", status)
+	}
+}
+
+func TestStatDisk(t *testing.T) {
+	free, total, err := statDisk(os.TempDir())
+	if err == ErrUnsupported {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total == 0 || free > total {
+		t.Fatalf("Unexpected disk space, %v free of %v", free, total)
+	}
+}
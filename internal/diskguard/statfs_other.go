@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd)
+// +build !linux,!darwin,!freebsd
+
+package diskguard
+
+// statDisk is not supported on this platform, only the database size is guarded
+func statDisk(path string) (uint64, uint64, error) {
+	return 0, 0, ErrUnsupported
+}
@@ -0,0 +1,382 @@
+package ingest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/koinos/koinos-log-golang/v2"
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/broadcast"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+const (
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+	defaultQueueSize     = 1000
+)
+
+// CommitHandler is called once the transactions of an accepted block have been
+// committed, with the IDs of those that were added successfully
+type CommitHandler func(submission *broadcast.BlockAccepted, indexed [][]byte)
+
+// IrreversibleHandler is called with an irreversible block once the blocks
+// submitted before it have been committed
+type IrreversibleHandler func(topology *koinos.BlockTopology)
+
+// Option configures optional Ingester behavior
+type Option func(*Ingester)
+
+// WithBatchSize sets the number of blocks committed together, each block is committed on its own if less than 2
+func WithBatchSize(size int) Option {
+	return func(i *Ingester) {
+		i.batchSize = size
+	}
+}
+
+// WithFlushInterval sets the longest time a block waits for its batch to fill
+func WithFlushInterval(interval time.Duration) Option {
+	return func(i *Ingester) {
+		i.flushInterval = interval
+	}
+}
+
+// WithQueueSize sets the number of submitted blocks that may wait to be committed, which is at least the batch size
+func WithQueueSize(size int) Option {
+	return func(i *Ingester) {
+		i.queueSize = size
+	}
+}
+
+// WithIrreversibleHandler sets the handler called with the irreversible blocks submitted with SubmitIrreversible
+func WithIrreversibleHandler(onIrreversible IrreversibleHandler) Option {
+	return func(i *Ingester) {
+		i.onIrreversible = onIrreversible
+	}
+}
+
+// WithHoldBack sets a function called before each batch is committed, which
+// blocks while blocks must not be added. Submitted blocks stay queued until it
+// returns, and are not committed if it returns an error.
+func WithHoldBack(holdBack func(ctx context.Context) error) Option {
+	return func(i *Ingester) {
+		i.holdBack = holdBack
+	}
+}
+
+// Ingester adds the transactions of accepted blocks to the store in batches.
+// Broadcasts are decoded in parallel by the handlers that submit them, and
+// their transactions are committed a batch of blocks at a time by a single
+// goroutine. Sync blocks wait for their batch to fill, a live block is
+// committed as soon as it is submitted. Irreversible blocks are handled by the
+// same goroutine, once the blocks submitted before them have been committed.
+//
+// Submit only blocks while the queue is full, so that the handlers submitting
+// blocks, which share their goroutines with RPC requests, are not held up while
+// ingestion is briefly held back, and the queue does not grow without bound
+// while it is held back for longer.
+type Ingester struct {
+	store          *trxstore.TransactionStore
+	onCommit       CommitHandler
+	onIrreversible IrreversibleHandler
+	holdBack       func(ctx context.Context) error
+
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+
+	mutex   sync.Mutex
+	queue   []*broadcast.BlockAccepted
+	live    bool
+	queued  chan struct{}
+	flushes chan struct{}
+
+	// pending counts the submitted blocks that have not been committed, at most
+	// queueSize, committed is signaled when it decreases or when the ingester stops
+	pending   int
+	stopped   bool
+	committed *sync.Cond
+
+	// irreversible holds the irreversible blocks waiting for the blocks submitted
+	// before them, which are counted by submitted and committedCount
+	irreversible   []irreversibleBlock
+	submitted      uint64
+	committedCount uint64
+}
+
+type irreversibleBlock struct {
+	topology  *koinos.BlockTopology
+	submitted uint64
+}
+
+// NewIngester creates an Ingester adding blocks to the given store
+func NewIngester(store *trxstore.TransactionStore, onCommit CommitHandler, opts ...Option) *Ingester {
+	i := &Ingester{
+		store:         store,
+		onCommit:      onCommit,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		queueSize:     defaultQueueSize,
+		queued:        make(chan struct{}, 1),
+		flushes:       make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	if i.batchSize < 1 {
+		i.batchSize = 1
+	}
+	if i.queueSize < i.batchSize {
+		i.queueSize = i.batchSize
+	}
+	i.committed = sync.NewCond(&i.mutex)
+
+	return i
+}
+
+// Submit queues an accepted block to be committed with the next batch, waiting
+// while the queue is full. Blocks submitted once the ingester has stopped are dropped.
+func (i *Ingester) Submit(submission *broadcast.BlockAccepted) {
+	i.mutex.Lock()
+	for i.pending >= i.queueSize && !i.stopped {
+		i.committed.Wait()
+	}
+	if i.stopped {
+		i.mutex.Unlock()
+		log.Warnf("Dropped block %v submitted after ingestion stopped", submission.GetBlock().GetHeader().GetHeight())
+		return
+	}
+	i.pending++
+	i.submitted++
+	i.queue = append(i.queue, submission)
+	i.live = i.live || submission.GetLive()
+	i.mutex.Unlock()
+
+	select {
+	case i.queued <- struct{}{}:
+	default:
+	}
+}
+
+// SubmitIrreversible queues an irreversible block to be handled once the blocks
+// submitted before it have been committed, so that it never overtakes them
+func (i *Ingester) SubmitIrreversible(topology *koinos.BlockTopology) {
+	i.mutex.Lock()
+	if i.stopped {
+		i.mutex.Unlock()
+		log.Warnf("Dropped irreversible block %v submitted after ingestion stopped", topology.GetHeight())
+		return
+	}
+	i.irreversible = append(i.irreversible, irreversibleBlock{topology: topology, submitted: i.submitted})
+	i.mutex.Unlock()
+
+	select {
+	case i.queued <- struct{}{}:
+	default:
+	}
+}
+
+// Flush commits the blocks that have been submitted and waits until they are
+// committed, or until the ingester stops
+func (i *Ingester) Flush() {
+	select {
+	case i.flushes <- struct{}{}:
+	default:
+	}
+	i.wait()
+}
+
+// wait blocks until every submitted block is committed or the ingester stops
+func (i *Ingester) wait() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	for i.pending > 0 && !i.stopped {
+		i.committed.Wait()
+	}
+}
+
+// Run commits submitted blocks until the context is done. The blocks queued by
+// then are committed before it returns, unless ingestion is held back, so that
+// the store may be closed once it has returned.
+func (i *Ingester) Run(ctx context.Context) {
+	defer i.stop()
+
+	timer := time.NewTimer(i.flushInterval)
+	defer timer.Stop()
+
+	for {
+		flush := false
+		done := false
+		select {
+		case <-i.queued:
+		case <-i.flushes:
+			flush = true
+		case <-timer.C:
+			timer.Reset(i.flushInterval)
+			flush = true
+		case <-ctx.Done():
+			flush = true
+			done = true
+		}
+
+		for {
+			batch := i.nextBatch(flush)
+			if len(batch) == 0 {
+				break
+			}
+
+			if i.holdBack != nil {
+				if err := i.holdBack(ctx); err != nil {
+					return
+				}
+			}
+			i.commit(batch)
+			i.handleIrreversible()
+		}
+		i.handleIrreversible()
+
+		if done {
+			return
+		}
+	}
+}
+
+// nextBatch removes the next batch from the queue. A partial batch is only
+// removed when flushing or when a live block is queued.
+func (i *Ingester) nextBatch(flush bool) []*broadcast.BlockAccepted {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if len(i.queue) == 0 || (len(i.queue) < i.batchSize && !flush && !i.live) {
+		return nil
+	}
+
+	size := len(i.queue)
+	if size > i.batchSize {
+		size = i.batchSize
+	}
+	batch := i.queue[:size:size]
+	i.queue = i.queue[size:]
+	if len(i.queue) == 0 {
+		i.queue = nil
+		i.live = false
+	}
+
+	return batch
+}
+
+// stop releases the callers waiting for blocks that will no longer be committed
+func (i *Ingester) stop() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.pending > 0 {
+		log.Warnf("Dropped %v block(s) that were not committed before ingestion stopped", i.pending)
+	}
+	i.stopped = true
+	i.committed.Broadcast()
+}
+
+// handleIrreversible handles the irreversible blocks whose preceding blocks have been committed
+func (i *Ingester) handleIrreversible() {
+	i.mutex.Lock()
+	count := 0
+	for count < len(i.irreversible) && i.irreversible[count].submitted <= i.committedCount {
+		count++
+	}
+	ready := i.irreversible[:count:count]
+	i.irreversible = i.irreversible[count:]
+	if len(i.irreversible) == 0 {
+		i.irreversible = nil
+	}
+	i.mutex.Unlock()
+
+	if i.onIrreversible == nil {
+		return
+	}
+	for _, block := range ready {
+		i.onIrreversible(block.topology)
+	}
+}
+
+func (i *Ingester) commit(batch []*broadcast.BlockAccepted) {
+	defer func() {
+		i.mutex.Lock()
+		defer i.mutex.Unlock()
+
+		i.pending -= len(batch)
+		i.committedCount += uint64(len(batch))
+		i.committed.Broadcast()
+	}()
+
+	trxs := make([]*trxstore.IncludedTransaction, 0)
+	for _, submission := range batch {
+		trxs = append(trxs, IncludedTransactions(submission)...)
+	}
+
+	err := i.store.AddIncludedTransactions(trxs)
+	if err != nil {
+		log.Warnf("Could not add a batch of %v blocks, adding their transactions one at a time: %s", len(batch), err.Error())
+	}
+
+	for _, submission := range batch {
+		var indexed [][]byte
+		if err == nil {
+			indexed = make([][]byte, len(submission.Block.Transactions))
+			for j, trx := range submission.Block.Transactions {
+				indexed[j] = trx.Id
+			}
+			putBlockTime(i.store, submission)
+		} else {
+			indexed = AddBlock(i.store, submission)
+		}
+
+		i.onCommit(submission, indexed)
+	}
+}
+
+// IncludedTransactions returns the transactions of an accepted block with their receipts
+func IncludedTransactions(submission *broadcast.BlockAccepted) []*trxstore.IncludedTransaction {
+	topology := &koinos.BlockTopology{
+		Id:       submission.Block.Id,
+		Height:   submission.Block.Header.Height,
+		Previous: submission.Block.Header.Previous,
+	}
+
+	receipts := make(map[string]*protocol.TransactionReceipt, len(submission.GetReceipt().GetTransactionReceipts()))
+	for _, receipt := range submission.GetReceipt().GetTransactionReceipts() {
+		receipts[string(receipt.Id)] = receipt
+	}
+
+	trxs := make([]*trxstore.IncludedTransaction, len(submission.Block.Transactions))
+	for j, trx := range submission.Block.Transactions {
+		trxs[j] = &trxstore.IncludedTransaction{Transaction: trx, Receipt: receipts[string(trx.Id)], Topology: topology}
+	}
+
+	return trxs
+}
+
+// AddBlock adds the transactions of an accepted block one at a time, returning the IDs of those that were added
+func AddBlock(store *trxstore.TransactionStore, submission *broadcast.BlockAccepted) [][]byte {
+	indexed := make([][]byte, 0, len(submission.Block.Transactions))
+	for _, trx := range IncludedTransactions(submission) {
+		if err := store.AddIncludedTransactionWithReceipt(trx.Transaction, trx.Receipt, trx.Topology); err != nil {
+			log.Warnf("could not add included transaction: %s", err)
+		} else {
+			indexed = append(indexed, trx.Transaction.Id)
+		}
+	}
+
+	putBlockTime(store, submission)
+	return indexed
+}
+
+// putBlockTime records the timestamp of an accepted block, which retention by age and resource usage by time rely on
+func putBlockTime(store *trxstore.TransactionStore, submission *broadcast.BlockAccepted) {
+	if err := store.PutBlockTime(submission.Block.Header.Height, submission.Block.Header.Timestamp); err != nil {
+		log.Warnf("could not record block time: %s", err)
+	}
+}
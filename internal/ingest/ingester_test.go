@@ -0,0 +1,315 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/broadcast"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+// makeTransaction returns a transaction whose ID is the hash of its header
+func makeTransaction(t *testing.T, n uint64) *protocol.Transaction {
+	header := &protocol.TransactionHeader{Payer: []byte{1}, RcLimit: n}
+	id, err := util.HashMessage(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &protocol.Transaction{Id: id, Header: header}
+}
+
+func makeSubmission(height uint64, trxs ...*protocol.Transaction) *broadcast.BlockAccepted {
+	block := &protocol.Block{
+		Id:           []byte{byte(height)},
+		Header:       &protocol.BlockHeader{Height: height, Previous: []byte{byte(height - 1)}, Timestamp: height * 1000},
+		Transactions: trxs,
+	}
+	receipt := &protocol.BlockReceipt{}
+	for _, trx := range trxs {
+		receipt.TransactionReceipts = append(receipt.TransactionReceipts, &protocol.TransactionReceipt{Id: trx.Id, Payer: []byte{1}, RcUsed: 1})
+	}
+
+	return &broadcast.BlockAccepted{Block: block, Receipt: receipt}
+}
+
+func TestIngester(t *testing.T) {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend(), trxstore.WithTransactionIDVerification(true), trxstore.WithEveryBlockTime(true))
+
+	var mutex sync.Mutex
+	committed := make(map[uint64][][]byte)
+	ingester := NewIngester(store, func(submission *broadcast.BlockAccepted, indexed [][]byte) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		committed[submission.Block.Header.Height] = indexed
+	}, WithBatchSize(3), WithFlushInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ingester.Run(ctx)
+
+	trxs := make([]*protocol.Transaction, 6)
+	for i := range trxs {
+		trxs[i] = makeTransaction(t, uint64(i))
+	}
+
+	// A full batch is committed without a flush
+	ingester.Submit(makeSubmission(1, trxs[0], trxs[1]))
+	ingester.Submit(makeSubmission(2, trxs[2]))
+	ingester.Submit(makeSubmission(3))
+	ingester.wait()
+
+	// A transaction that is rejected fails the batch, which is then added one transaction at a time
+	bad := makeTransaction(t, 100)
+	bad.Id = trxs[5].Id
+	ingester.Submit(makeSubmission(4, trxs[3]))
+	ingester.Submit(makeSubmission(5, trxs[4], bad))
+	ingester.Flush()
+
+	expected := map[uint64][]*protocol.Transaction{1: {trxs[0], trxs[1]}, 2: {trxs[2]}, 3: {}, 4: {trxs[3]}, 5: {trxs[4]}}
+	if len(committed) != len(expected) {
+		t.Fatalf("Expected %v committed blocks, got %v", len(expected), len(committed))
+	}
+	for height, expectedTrxs := range expected {
+		if len(committed[height]) != len(expectedTrxs) {
+			t.Fatalf("Expected %v indexed transactions at %v, got %v", len(expectedTrxs), height, len(committed[height]))
+		}
+		for i := range expectedTrxs {
+			if !bytes.Equal(committed[height][i], expectedTrxs[i].Id) {
+				t.Fatalf("Unexpected indexed transaction %v at %v", i, height)
+			}
+		}
+	}
+
+	ids := make([][]byte, len(trxs))
+	for i := range trxs {
+		ids[i] = trxs[i].Id
+	}
+	items, err := store.GetTransactionsByID(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 5 {
+		t.Fatalf("Expected 5 transactions, got %v", len(items))
+	}
+
+	usage, err := store.GetResourceUsage([]byte{1}, 0, trxstore.UsageBucketSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 1 || usage[0].Transactions != 5 {
+		t.Fatalf("Unexpected usage %+v", usage)
+	}
+
+	// Block times are recorded whether blocks were committed in a batch or not
+	for timestamp, expected := range map[uint64]uint64{2500: 3, 5000: 5, 5001: 6} {
+		height, err := store.HeightAt(timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != expected {
+			t.Fatalf("Expected height %v at %v, got %v", expected, timestamp, height)
+		}
+	}
+}
+
+func TestIngesterHoldBack(t *testing.T) {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+
+	committed := make(chan uint64, 10)
+	release := make(chan struct{})
+	ingester := NewIngester(store, func(submission *broadcast.BlockAccepted, indexed [][]byte) {
+		committed <- submission.Block.Header.Height
+	}, WithBatchSize(2), WithQueueSize(6), WithFlushInterval(time.Hour), WithHoldBack(func(ctx context.Context) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ingester.Run(ctx)
+
+	// Submitting does not block while ingestion is held back, until the queue is full
+	for height := uint64(1); height <= 5; height++ {
+		ingester.Submit(makeSubmission(height, makeTransaction(t, height)))
+	}
+
+	select {
+	case height := <-committed:
+		t.Fatalf("Block %v was committed while ingestion was held back", height)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A live block is committed with the blocks before it, without waiting for a full batch
+	live := makeSubmission(6)
+	live.Live = true
+	ingester.Submit(live)
+
+	submitted := make(chan struct{})
+	go func() {
+		full := makeSubmission(7)
+		full.Live = true
+		ingester.Submit(full)
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("A block was submitted while the queue was full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	select {
+	case <-submitted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("A block was not submitted once the queue had room")
+	}
+
+	for expected := uint64(1); expected <= 7; expected++ {
+		select {
+		case height := <-committed:
+			if height != expected {
+				t.Fatalf("Expected block %v to be committed, got %v", expected, height)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Block %v was not committed", expected)
+		}
+	}
+}
+
+func TestIngesterFlush(t *testing.T) {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+	ingester := NewIngester(store, func(*broadcast.BlockAccepted, [][]byte) {}, WithBatchSize(10), WithFlushInterval(time.Hour))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		ingester.Run(ctx)
+		close(stopped)
+	}()
+
+	// Blocks may be submitted while other handlers flush
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for height := uint64(1); height <= 20; height++ {
+				ingester.Submit(makeSubmission(height, makeTransaction(t, uint64(n)*100+height)))
+				if height%5 == 0 {
+					ingester.Flush()
+				}
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	cancel()
+	<-stopped
+
+	// Submitting and flushing once the ingester has stopped return, although the block is never committed
+	done := make(chan struct{})
+	go func() {
+		ingester.Submit(makeSubmission(21))
+		ingester.Flush()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Flush did not return once the ingester stopped")
+	}
+}
+
+func TestIngesterIrreversible(t *testing.T) {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+
+	events := make(chan string, 10)
+	ingester := NewIngester(store, func(submission *broadcast.BlockAccepted, indexed [][]byte) {
+		events <- fmt.Sprintf("accepted %v", submission.Block.Header.Height)
+	}, WithBatchSize(3), WithFlushInterval(time.Hour), WithIrreversibleHandler(func(topology *koinos.BlockTopology) {
+		events <- fmt.Sprintf("irreversible %v", topology.Height)
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ingester.Run(ctx)
+
+	expectEvents := func(expected ...string) {
+		for _, event := range expected {
+			select {
+			case actual := <-events:
+				if actual != event {
+					t.Fatalf("Expected %s, got %s", event, actual)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Expected %s", event)
+			}
+		}
+		select {
+		case actual := <-events:
+			t.Fatalf("Unexpected %s", actual)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// An irreversible block waits for the blocks submitted before it
+	ingester.Submit(makeSubmission(1))
+	ingester.Submit(makeSubmission(2))
+	ingester.SubmitIrreversible(&koinos.BlockTopology{Height: 1})
+	expectEvents()
+
+	ingester.Submit(makeSubmission(3))
+	expectEvents("accepted 1", "accepted 2", "accepted 3", "irreversible 1")
+
+	// And is handled right away once they have been committed
+	ingester.SubmitIrreversible(&koinos.BlockTopology{Height: 3})
+	expectEvents("irreversible 3")
+}
+
+func TestIngesterStop(t *testing.T) {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+
+	var committed []uint64
+	onCommit := func(submission *broadcast.BlockAccepted, indexed [][]byte) {
+		committed = append(committed, submission.Block.Header.Height)
+	}
+	ingester := NewIngester(store, onCommit, WithBatchSize(10), WithFlushInterval(time.Hour))
+
+	// The blocks queued when the context is done are committed before Run returns
+	for height := uint64(1); height <= 3; height++ {
+		ingester.Submit(makeSubmission(height, makeTransaction(t, height)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ingester.Run(ctx)
+
+	if len(committed) != 3 {
+		t.Fatalf("Expected 3 committed blocks, got %v", committed)
+	}
+
+	// Unless ingestion is held back
+	ingester = NewIngester(store, onCommit, WithBatchSize(10), WithFlushInterval(time.Hour), WithHoldBack(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	ingester.Submit(makeSubmission(4, makeTransaction(t, 4)))
+	ingester.Run(ctx)
+
+	if len(committed) != 3 {
+		t.Fatalf("Expected no more committed blocks, got %v", committed)
+	}
+}
@@ -0,0 +1,6 @@
+package notify
+
+// BlockIndexedTopic is the topic notifypb.BlockIndexed broadcasts are published on. They are
+// broadcast once the transactions of an accepted block have been stored and indexed, so that
+// services depending on the store can wait for them rather than for the block itself.
+const BlockIndexedTopic = "koinos.transaction_store.indexed"
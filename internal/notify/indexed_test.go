@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-transaction-store/internal/notify/notifypb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBlockIndexed(t *testing.T) {
+	topology := &koinos.BlockTopology{Id: []byte{1}, Height: 2, Previous: []byte{3}}
+	topologyBytes, err := proto.Marshal(topology)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Broadcasts published before the message was generated must still be read
+	data := protowire.AppendTag(nil, 1, protowire.BytesType)
+	data = protowire.AppendBytes(data, topologyBytes)
+	for _, id := range [][]byte{{4}, {5, 6}} {
+		data = protowire.AppendTag(data, 2, protowire.BytesType)
+		data = protowire.AppendBytes(data, id)
+	}
+
+	decoded := &notifypb.BlockIndexed{}
+	if err := proto.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(decoded.Topology, topology) {
+		t.Fatalf("Unexpected topology %v", decoded.Topology)
+	}
+	if len(decoded.TransactionIds) != 2 || !bytes.Equal(decoded.TransactionIds[0], []byte{4}) || !bytes.Equal(decoded.TransactionIds[1], []byte{5, 6}) {
+		t.Fatalf("Unexpected transaction IDs %v", decoded.TransactionIds)
+	}
+
+	encoded, err := proto.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(encoded, data) {
+		t.Fatalf("Expected %x, got %x", data, encoded)
+	}
+
+	if err := proto.Unmarshal(data[:len(data)-1], decoded); err == nil {
+		t.Fatal("Expected an error decoding a truncated broadcast")
+	}
+}
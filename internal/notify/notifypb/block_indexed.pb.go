@@ -0,0 +1,167 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: block_indexed.proto
+
+package notifypb
+
+import (
+	koinos "github.com/koinos/koinos-proto-golang/v2/koinos"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Broadcast once the transactions of an accepted block have been stored and
+// indexed, so that services depending on the store can wait for it rather
+// than for the block itself
+type BlockIndexed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Topology       *koinos.BlockTopology `protobuf:"bytes,1,opt,name=topology,proto3" json:"topology,omitempty"`
+	TransactionIds [][]byte              `protobuf:"bytes,2,rep,name=transaction_ids,json=transactionIds,proto3" json:"transaction_ids,omitempty"`
+}
+
+func (x *BlockIndexed) Reset() {
+	*x = BlockIndexed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_block_indexed_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockIndexed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockIndexed) ProtoMessage() {}
+
+func (x *BlockIndexed) ProtoReflect() protoreflect.Message {
+	mi := &file_block_indexed_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockIndexed.ProtoReflect.Descriptor instead.
+func (*BlockIndexed) Descriptor() ([]byte, []int) {
+	return file_block_indexed_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BlockIndexed) GetTopology() *koinos.BlockTopology {
+	if x != nil {
+		return x.Topology
+	}
+	return nil
+}
+
+func (x *BlockIndexed) GetTransactionIds() [][]byte {
+	if x != nil {
+		return x.TransactionIds
+	}
+	return nil
+}
+
+var File_block_indexed_proto protoreflect.FileDescriptor
+
+var file_block_indexed_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x64, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1f, 0x6b, 0x6f, 0x69, 0x6e, 0x6f, 0x73, 0x2e, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2e,
+	0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x1a, 0x13, 0x6b, 0x6f, 0x69, 0x6e, 0x6f, 0x73, 0x2f, 0x63,
+	0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6c, 0x0a, 0x0d, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x64, 0x12, 0x32, 0x0a, 0x08,
+	0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x6b, 0x6f, 0x69, 0x6e, 0x6f, 0x73, 0x2e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x6f,
+	0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79, 0x52, 0x08, 0x74, 0x6f, 0x70, 0x6f, 0x6c, 0x6f, 0x67, 0x79,
+	0x12, 0x27, 0x0a, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x73, 0x42, 0x45, 0x5a, 0x43, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x6f, 0x69, 0x6e, 0x6f, 0x73, 0x2f, 0x6b,
+	0x6f, 0x69, 0x6e, 0x6f, 0x73, 0x2d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x2d, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x2f, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x2f, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x70, 0x62,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_block_indexed_proto_rawDescOnce sync.Once
+	file_block_indexed_proto_rawDescData = file_block_indexed_proto_rawDesc
+)
+
+func file_block_indexed_proto_rawDescGZIP() []byte {
+	file_block_indexed_proto_rawDescOnce.Do(func() {
+		file_block_indexed_proto_rawDescData = protoimpl.X.CompressGZIP(file_block_indexed_proto_rawDescData)
+	})
+	return file_block_indexed_proto_rawDescData
+}
+
+var file_block_indexed_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_block_indexed_proto_goTypes = []interface{}{
+	(*BlockIndexed)(nil),         // 0: koinos.transaction_store.notify.block_indexed
+	(*koinos.BlockTopology)(nil), // 1: koinos.block_topology
+}
+var file_block_indexed_proto_depIdxs = []int32{
+	1, // 0: koinos.transaction_store.notify.block_indexed.topology:type_name -> koinos.block_topology
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_block_indexed_proto_init() }
+func file_block_indexed_proto_init() {
+	if File_block_indexed_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_block_indexed_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockIndexed); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_block_indexed_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_block_indexed_proto_goTypes,
+		DependencyIndexes: file_block_indexed_proto_depIdxs,
+		MessageInfos:      file_block_indexed_proto_msgTypes,
+	}.Build()
+	File_block_indexed_proto = out.File
+	file_block_indexed_proto_rawDesc = nil
+	file_block_indexed_proto_goTypes = nil
+	file_block_indexed_proto_depIdxs = nil
+}
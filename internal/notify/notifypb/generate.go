@@ -0,0 +1,6 @@
+// Package notifypb holds the messages of block_indexed.proto, generated with
+// protoc-gen-go. KOINOS_PROTO must name a checkout of koinos-proto, which the
+// koinos imports are resolved from.
+package notifypb
+
+//go:generate protoc -I . -I $KOINOS_PROTO --go_out=. --go_opt=paths=source_relative block_indexed.proto
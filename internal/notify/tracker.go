@@ -0,0 +1,230 @@
+package notify
+
+import (
+	"bytes"
+	"sync"
+
+	log "github.com/koinos/koinos-log-golang/v2"
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+// NotificationType is the change in a transaction's inclusion that a notification reports
+type NotificationType string
+
+// Notification types
+const (
+	// Included is reported when a block containing the transaction becomes part of the head chain
+	Included NotificationType = "included"
+
+	// Reorged is reported when the head chain no longer contains the transaction
+	Reorged NotificationType = "reorged"
+
+	// Irreversible is reported when a block containing the transaction becomes irreversible
+	Irreversible NotificationType = "irreversible"
+)
+
+// Notification reports a change in the inclusion of a transaction
+type Notification struct {
+	Type          NotificationType
+	TransactionID []byte
+	BlockID       []byte
+	Height        uint64
+	Transaction   *protocol.Transaction
+
+	// Addresses are the payer, payee and signers of the transaction, and the
+	// addresses impacted by the events in its receipt
+	Addresses [][]byte
+}
+
+// Listener receives notifications. Listeners are called synchronously, in
+// order, and must not block.
+type Listener func(n *Notification)
+
+type trackedTransaction struct {
+	transaction *protocol.Transaction
+	addresses   [][]byte
+}
+
+type trackedBlock struct {
+	id           []byte
+	previous     []byte
+	height       uint64
+	transactions []*trackedTransaction
+}
+
+// Tracker follows the head chain through accepted and irreversible blocks and
+// notifies listeners as transactions are included in it, removed from it by a
+// fork switch, or become irreversible. Only blocks that are still reversible
+// are kept.
+type Tracker struct {
+	store     *trxstore.TransactionStore
+	mutex     sync.Mutex
+	listeners []Listener
+	blocks    map[string]*trackedBlock
+	head      *trackedBlock
+}
+
+// NewTracker creates a Tracker that looks up transaction signers in the given store
+func NewTracker(store *trxstore.TransactionStore) *Tracker {
+	return &Tracker{
+		store:  store,
+		blocks: make(map[string]*trackedBlock),
+	}
+}
+
+// AddListener registers a listener for every following notification
+func (t *Tracker) AddListener(listener Listener) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.listeners = append(t.listeners, listener)
+}
+
+// BlockAccepted tracks an accepted block with its receipt, which may be nil.
+// Its transactions must already have been added to the store so that their
+// signers are known.
+func (t *Tracker) BlockAccepted(block *protocol.Block, receipt *protocol.BlockReceipt, head bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	b, ok := t.blocks[string(block.Id)]
+	if !ok {
+		b = &trackedBlock{
+			id:           block.Id,
+			previous:     block.GetHeader().GetPrevious(),
+			height:       block.GetHeader().GetHeight(),
+			transactions: make([]*trackedTransaction, len(block.Transactions)),
+		}
+		receipts := make(map[string]*protocol.TransactionReceipt, len(receipt.GetTransactionReceipts()))
+		for _, trxReceipt := range receipt.GetTransactionReceipts() {
+			receipts[string(trxReceipt.Id)] = trxReceipt
+		}
+		for i, tx := range block.Transactions {
+			b.transactions[i] = &trackedTransaction{transaction: tx, addresses: t.addresses(tx, receipts[string(tx.Id)])}
+		}
+		t.blocks[string(block.Id)] = b
+	}
+
+	if !head || t.head == b {
+		return
+	}
+
+	// The blocks joining the head chain, from the new head down
+	joined := []*trackedBlock{b}
+	var left []*trackedBlock
+
+	if t.head != nil && !bytes.Equal(b.previous, t.head.id) {
+		onNewChain := map[string]int{string(b.id): 0}
+		for parent, ok := t.blocks[string(b.previous)]; ok; parent, ok = t.blocks[string(parent.previous)] {
+			onNewChain[string(parent.id)] = len(joined)
+			joined = append(joined, parent)
+		}
+
+		ancestor := -1
+		for old := t.head; old != nil; old = t.blocks[string(old.previous)] {
+			if i, ok := onNewChain[string(old.id)]; ok {
+				ancestor = i
+				break
+			}
+			left = append(left, old)
+		}
+
+		if ancestor >= 0 {
+			joined = joined[:ancestor]
+		} else {
+			// The fork is deeper than the tracked blocks
+			joined = joined[:1]
+		}
+	}
+
+	t.head = b
+
+	stillIncluded := make(map[string]struct{})
+	for _, block := range joined {
+		for _, tx := range block.transactions {
+			stillIncluded[string(tx.transaction.Id)] = struct{}{}
+		}
+	}
+
+	for _, block := range left {
+		for _, tx := range block.transactions {
+			if _, ok := stillIncluded[string(tx.transaction.Id)]; !ok {
+				t.notify(Reorged, block, tx)
+			}
+		}
+	}
+
+	for i := len(joined) - 1; i >= 0; i-- {
+		for _, tx := range joined[i].transactions {
+			t.notify(Included, joined[i], tx)
+		}
+	}
+}
+
+// BlockIrreversible reports the transactions of a block that became
+// irreversible and forgets every block at or below its height
+func (t *Tracker) BlockIrreversible(topology *koinos.BlockTopology) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if b, ok := t.blocks[string(topology.Id)]; ok {
+		for _, tx := range b.transactions {
+			t.notify(Irreversible, b, tx)
+		}
+	}
+
+	for id, b := range t.blocks {
+		if b.height <= topology.Height {
+			delete(t.blocks, id)
+		}
+	}
+}
+
+func (t *Tracker) notify(notificationType NotificationType, b *trackedBlock, tx *trackedTransaction) {
+	n := &Notification{
+		Type:          notificationType,
+		TransactionID: tx.transaction.Id,
+		BlockID:       b.id,
+		Height:        b.height,
+		Transaction:   tx.transaction,
+		Addresses:     tx.addresses,
+	}
+
+	for _, listener := range t.listeners {
+		listener(n)
+	}
+}
+
+// addresses returns the payer, payee and signers of a transaction, followed by
+// the addresses impacted by the events of its receipt, without duplicates
+func (t *Tracker) addresses(tx *protocol.Transaction, receipt *protocol.TransactionReceipt) [][]byte {
+	addresses := make([][]byte, 0, 2+len(tx.Signatures))
+	seen := make(map[string]struct{})
+	add := func(address []byte) {
+		if _, ok := seen[string(address)]; len(address) > 0 && !ok {
+			seen[string(address)] = struct{}{}
+			addresses = append(addresses, address)
+		}
+	}
+
+	add(tx.GetHeader().GetPayer())
+	add(tx.GetHeader().GetPayee())
+
+	signers, err := t.store.GetTransactionSigners(tx.Id)
+	if err != nil {
+		log.Warnf("Could not get signers of transaction 0x%x: %s", tx.Id, err.Error())
+	}
+	for _, signer := range signers {
+		add(signer)
+	}
+
+	for _, event := range receipt.GetEvents() {
+		for _, impacted := range event.Impacted {
+			add(impacted)
+		}
+	}
+
+	return addresses
+}
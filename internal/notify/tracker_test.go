@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+func makeBlock(id byte, previous byte, height uint64, trxIDs ...byte) *protocol.Block {
+	block := &protocol.Block{
+		Id:     []byte{id},
+		Header: &protocol.BlockHeader{Previous: []byte{previous}, Height: height},
+	}
+	for _, trxID := range trxIDs {
+		block.Transactions = append(block.Transactions, &protocol.Transaction{
+			Id:     []byte{trxID},
+			Header: &protocol.TransactionHeader{Payer: []byte{0, trxID}},
+		})
+	}
+
+	return block
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(trxstore.NewTransactionStore(trxstore.NewMapBackend()))
+
+	var notifications []string
+	tracker.AddListener(func(n *Notification) {
+		if !bytes.Equal(n.Addresses[0], []byte{0, n.TransactionID[0]}) {
+			t.Fatal("Expected the payer address")
+		}
+		notifications = append(notifications, fmt.Sprintf("%s %v@%v", n.Type, n.TransactionID[0], n.BlockID[0]))
+	})
+
+	expect := func(expected ...string) {
+		if len(notifications) != len(expected) {
+			t.Fatalf("Expected %v, got %v", expected, notifications)
+		}
+		for i := range expected {
+			if notifications[i] != expected[i] {
+				t.Fatalf("Expected %v, got %v", expected, notifications)
+			}
+		}
+		notifications = nil
+	}
+
+	tracker.BlockAccepted(makeBlock(1, 0, 1, 10), nil, true)
+	tracker.BlockAccepted(makeBlock(2, 1, 2, 20, 21), nil, true)
+	expect("included 10@1", "included 20@2", "included 21@2")
+
+	// A block that is not the head is not reported until it becomes part of the head chain
+	tracker.BlockAccepted(makeBlock(3, 1, 2, 21, 30), nil, false)
+	tracker.BlockAccepted(makeBlock(4, 3, 3, 40), nil, false)
+	expect()
+
+	// Switching forks reports the transactions left behind and those joined
+	tracker.BlockAccepted(makeBlock(5, 4, 4, 50), nil, true)
+	expect("reorged 20@2", "included 21@3", "included 30@3", "included 40@4", "included 50@5")
+
+	// Accepting the head again does nothing
+	tracker.BlockAccepted(makeBlock(5, 4, 4, 50), nil, true)
+	expect()
+
+	tracker.BlockIrreversible(&koinos.BlockTopology{Id: []byte{3}, Height: 2})
+	expect("irreversible 21@3", "irreversible 30@3")
+
+	// Blocks at or below the irreversible height are forgotten
+	tracker.BlockIrreversible(&koinos.BlockTopology{Id: []byte{2}, Height: 2})
+	expect()
+
+	tracker.BlockAccepted(makeBlock(6, 5, 5), nil, true)
+	tracker.BlockAccepted(makeBlock(7, 5, 5, 70), nil, true)
+	expect("included 70@7")
+}
+
+func TestTrackerImpactedAddresses(t *testing.T) {
+	tracker := NewTracker(trxstore.NewTransactionStore(trxstore.NewMapBackend()))
+
+	var addresses [][]byte
+	tracker.AddListener(func(n *Notification) {
+		addresses = n.Addresses
+	})
+
+	// A deposit is only visible through the addresses impacted by the transfer event
+	block := makeBlock(1, 0, 1, 10)
+	receipt := &protocol.BlockReceipt{TransactionReceipts: []*protocol.TransactionReceipt{{
+		Id: []byte{10},
+		Events: []*protocol.EventData{
+			{Name: "transfer", Impacted: [][]byte{{0, 20}, {0, 10}}},
+			{Name: "transfer", Impacted: [][]byte{{0, 20}, {0, 30}}},
+		},
+	}}}
+	tracker.BlockAccepted(block, receipt, true)
+
+	expected := [][]byte{{0, 10}, {0, 20}, {0, 30}}
+	if len(addresses) != len(expected) {
+		t.Fatalf("Expected addresses %v, got %v", expected, addresses)
+	}
+	for i := range expected {
+		if !bytes.Equal(addresses[i], expected[i]) {
+			t.Fatalf("Expected addresses %v, got %v", expected, addresses)
+		}
+	}
+}
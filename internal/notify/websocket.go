@@ -0,0 +1,306 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/koinos/koinos-log-golang/v2"
+	"github.com/koinos/koinos-transaction-store/internal/query"
+	"golang.org/x/net/websocket"
+)
+
+// Subscription methods
+const (
+	SubscribeMethod   = "subscribe"
+	UnsubscribeMethod = "unsubscribe"
+)
+
+const (
+	// subscriberBufferSize is the number of notifications queued for a subscriber
+	// before it is considered too slow and disconnected
+	subscriberBufferSize = 256
+
+	defaultMaxSubscriptions = 1000
+	defaultWriteTimeout     = 10 * time.Second
+)
+
+// ErrSubscriptionLimit occurs when a subscriber subscribes to more transaction IDs and addresses than allowed
+var ErrSubscriptionLimit = errors.New("subscription limit reached")
+
+// SubscriptionParams selects the transactions a subscriber is notified about
+type SubscriptionParams struct {
+	TransactionIDs []query.HexBytes    `json:"transaction_ids,omitempty"`
+	Addresses      []query.Base58Bytes `json:"addresses,omitempty"`
+}
+
+// SubscriptionRequest is a JSON encoded message sent by a subscriber
+type SubscriptionRequest struct {
+	Method string              `json:"method"`
+	Params *SubscriptionParams `json:"params"`
+}
+
+// NotificationMessage is a JSON encoded notification sent to a subscriber
+type NotificationMessage struct {
+	Type          NotificationType `json:"type"`
+	TransactionID query.HexBytes   `json:"transaction_id"`
+	BlockID       query.HexBytes   `json:"block_id"`
+	Height        string           `json:"height"`
+}
+
+type subscriber struct {
+	mutex            sync.Mutex
+	transactionIDs   map[string]struct{}
+	addresses        map[string]struct{}
+	maxSubscriptions int
+	messages         chan interface{}
+}
+
+func (s *subscriber) update(request *SubscriptionRequest) error {
+	if request.Params == nil {
+		return errors.New("missing params")
+	}
+
+	var update func(set map[string]struct{}, key []byte)
+	switch request.Method {
+	case SubscribeMethod:
+		update = func(set map[string]struct{}, key []byte) { set[string(key)] = struct{}{} }
+	case UnsubscribeMethod:
+		update = func(set map[string]struct{}, key []byte) { delete(set, string(key)) }
+	default:
+		return fmt.Errorf("%w: %s", query.ErrUnknownMethod, request.Method)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// A subscription is refused as a whole if it would exceed the limit
+	if request.Method == SubscribeMethod {
+		newIDs := make(map[string]struct{})
+		for _, id := range request.Params.TransactionIDs {
+			if _, ok := s.transactionIDs[string(id)]; !ok {
+				newIDs[string(id)] = struct{}{}
+			}
+		}
+		newAddresses := make(map[string]struct{})
+		for _, address := range request.Params.Addresses {
+			if _, ok := s.addresses[string(address)]; !ok {
+				newAddresses[string(address)] = struct{}{}
+			}
+		}
+		if len(s.transactionIDs)+len(s.addresses)+len(newIDs)+len(newAddresses) > s.maxSubscriptions {
+			return fmt.Errorf("%w, at most %v transaction IDs and addresses may be subscribed to", ErrSubscriptionLimit, s.maxSubscriptions)
+		}
+	}
+
+	for _, id := range request.Params.TransactionIDs {
+		update(s.transactionIDs, id)
+	}
+	for _, address := range request.Params.Addresses {
+		update(s.addresses, address)
+	}
+
+	return nil
+}
+
+func (s *subscriber) matches(n *Notification) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.transactionIDs[string(n.TransactionID)]; ok {
+		return true
+	}
+	for _, address := range n.Addresses {
+		if _, ok := s.addresses[string(address)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HubOption configures optional Hub behavior
+type HubOption func(*Hub)
+
+// WithAllowedOrigins sets the origins browsers may subscribe from, "*" allows
+// every origin. By default only requests without an Origin header, or with one
+// matching the requested host, are accepted.
+func WithAllowedOrigins(origins ...string) HubOption {
+	return func(h *Hub) {
+		h.allowedOrigins = origins
+	}
+}
+
+// WithMaxSubscriptions sets the number of transaction IDs and addresses a subscriber may subscribe to
+func WithMaxSubscriptions(max int) HubOption {
+	return func(h *Hub) {
+		h.maxSubscriptions = max
+	}
+}
+
+// WithWriteTimeout sets how long a message may take to be sent to a subscriber before it is disconnected
+func WithWriteTimeout(timeout time.Duration) HubOption {
+	return func(h *Hub) {
+		h.writeTimeout = timeout
+	}
+}
+
+// Hub pushes notifications to WebSocket subscribers.
+//
+// Subscribers send SubscriptionRequest messages to add or remove transaction
+// IDs and addresses, answered by a query.Response, and receive a
+// NotificationMessage for every notification about a matching transaction. Subscribers that do not keep up are disconnected.
+type Hub struct {
+	mutex       sync.Mutex
+	subscribers map[*subscriber]struct{}
+
+	allowedOrigins   []string
+	maxSubscriptions int
+	writeTimeout     time.Duration
+}
+
+// NewHub creates a Hub without subscribers
+func NewHub(opts ...HubOption) *Hub {
+	h := &Hub{
+		subscribers:      make(map[*subscriber]struct{}),
+		maxSubscriptions: defaultMaxSubscriptions,
+		writeTimeout:     defaultWriteTimeout,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Notify is a Listener that queues the notification for matching subscribers
+func (h *Hub) Notify(n *Notification) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for s := range h.subscribers {
+		if !s.matches(n) {
+			continue
+		}
+
+		message := &NotificationMessage{
+			Type:          n.Type,
+			TransactionID: n.TransactionID,
+			BlockID:       n.BlockID,
+			Height:        strconv.FormatUint(n.Height, 10),
+		}
+
+		select {
+		case s.messages <- message:
+		default:
+			log.Warn("Disconnecting slow subscriber")
+			h.remove(s)
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler, accepting WebSocket subscribers from allowed origins
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Server{Handler: h.serve, Handshake: h.checkOrigin}.ServeHTTP(w, r)
+}
+
+// checkOrigin refuses the handshake of a browser on a page from an origin that is not allowed
+func (h *Hub) checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		return nil
+	}
+
+	if len(h.allowedOrigins) == 0 {
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return nil
+		}
+	}
+	for _, allowed := range h.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("origin %s is not allowed", origin)
+}
+
+func (h *Hub) serve(ws *websocket.Conn) {
+	s := &subscriber{
+		transactionIDs:   make(map[string]struct{}),
+		addresses:        make(map[string]struct{}),
+		maxSubscriptions: h.maxSubscriptions,
+		messages:         make(chan interface{}, subscriberBufferSize),
+	}
+
+	h.mutex.Lock()
+	h.subscribers[s] = struct{}{}
+	h.mutex.Unlock()
+
+	defer func() {
+		h.mutex.Lock()
+		h.remove(s)
+		h.mutex.Unlock()
+		ws.Close()
+	}()
+
+	go func() {
+		for {
+			var data []byte
+			if err := websocket.Message.Receive(ws, &data); err != nil {
+				h.mutex.Lock()
+				h.remove(s)
+				h.mutex.Unlock()
+				return
+			}
+
+			// Every request is answered so that subscribers know when a subscription is in effect
+			request := &SubscriptionRequest{}
+			if err := json.Unmarshal(data, request); err != nil {
+				h.reply(s, &query.Response{Error: &query.Error{Message: fmt.Sprintf("malformed request, %v", err)}})
+			} else if err := s.update(request); err != nil {
+				h.reply(s, &query.Response{Error: &query.Error{Message: err.Error()}})
+			} else {
+				h.reply(s, &query.Response{Result: true})
+			}
+		}
+	}()
+
+	// The HTTP server's timeouts no longer apply once the connection is hijacked, so that a subscriber
+	// may stay idle indefinitely, and each message sets its own write deadline instead
+	for message := range s.messages {
+		if err := ws.SetWriteDeadline(time.Now().Add(h.writeTimeout)); err != nil {
+			return
+		}
+		if err := websocket.JSON.Send(ws, message); err != nil {
+			return
+		}
+	}
+}
+
+// reply queues a message for the subscriber, dropping it if the queue is full
+func (h *Hub) reply(s *subscriber, message interface{}) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := h.subscribers[s]; ok {
+		select {
+		case s.messages <- message:
+		default:
+		}
+	}
+}
+
+// remove disconnects a subscriber, the hub mutex must be held
+func (h *Hub) remove(s *subscriber) {
+	if _, ok := h.subscribers[s]; ok {
+		delete(h.subscribers, s)
+		close(s.messages)
+	}
+}
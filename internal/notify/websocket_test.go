@@ -0,0 +1,168 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/koinos/koinos-transaction-store/internal/query"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocket(t *testing.T) {
+	tracker := NewTracker(trxstore.NewTransactionStore(trxstore.NewMapBackend()))
+	hub := NewHub()
+	tracker.AddListener(hub.Notify)
+
+	server := httptest.NewServer(hub)
+	defer server.Close()
+
+	ws, err := websocket.Dial(strings.Replace(server.URL, "http", "ws", 1), "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.SetDeadline(time.Now().Add(5 * time.Second))
+
+	request := func(message string) *query.Response {
+		if err := websocket.Message.Send(ws, message); err != nil {
+			t.Fatal(err)
+		}
+		response := &query.Response{}
+		if err := websocket.JSON.Receive(ws, response); err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	if response := request(`{"method":"subscribe","params":{"transaction_ids":["0x0a"],"addresses":["15"]}}`); response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+	if response := request(`{"method":"unsubscribe","params":{"transaction_ids":["0x0a"]}}`); response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+	if response := request(`{"method":"subscribe","params":{"transaction_ids":["0x0b"]}}`); response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+	if response := request(`{"method":"watch","params":{}}`); response.Error == nil || !strings.Contains(response.Error.Message, "unknown method") {
+		t.Fatal("Expected unknown method error")
+	}
+	if response := request(`{"method":`); response.Error == nil || !strings.Contains(response.Error.Message, "malformed request") {
+		t.Fatal("Expected malformed request error")
+	}
+
+	// Transaction 10 is no longer subscribed to, 11 is by ID and 4 by payer address
+	tracker.BlockAccepted(makeBlock(1, 0, 1, 10, 11), nil, true)
+	tracker.BlockAccepted(makeBlock(2, 1, 2, 4), nil, true)
+
+	for _, expected := range []NotificationMessage{
+		{Type: Included, TransactionID: []byte{11}, BlockID: []byte{1}, Height: "1"},
+		{Type: Included, TransactionID: []byte{4}, BlockID: []byte{2}, Height: "2"},
+	} {
+		var message NotificationMessage
+		if err := websocket.JSON.Receive(ws, &message); err != nil {
+			t.Fatal(err)
+		}
+		expectedJSON, _ := json.Marshal(expected)
+		messageJSON, _ := json.Marshal(message)
+		if string(expectedJSON) != string(messageJSON) {
+			t.Fatalf("Expected %s, got %s", expectedJSON, messageJSON)
+		}
+	}
+}
+
+func TestWebSocketLimits(t *testing.T) {
+	hub := NewHub(WithMaxSubscriptions(2), WithAllowedOrigins("https://wallet.example"))
+	server := httptest.NewServer(hub)
+	defer server.Close()
+	wsURL := strings.Replace(server.URL, "http", "ws", 1)
+
+	// Pages from other origins may not subscribe
+	if _, err := websocket.Dial(wsURL, "", "https://attacker.example"); err == nil {
+		t.Fatal("Expected a subscriber from a disallowed origin to be refused")
+	}
+
+	ws, err := websocket.Dial(wsURL, "", "https://wallet.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.SetDeadline(time.Now().Add(5 * time.Second))
+
+	request := func(message string) *query.Response {
+		if err := websocket.Message.Send(ws, message); err != nil {
+			t.Fatal(err)
+		}
+		response := &query.Response{}
+		if err := websocket.JSON.Receive(ws, response); err != nil {
+			t.Fatal(err)
+		}
+		return response
+	}
+
+	// Subscribing again to the same IDs does not count against the limit
+	if response := request(`{"method":"subscribe","params":{"transaction_ids":["0x0a","0x0a"],"addresses":["15"]}}`); response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+	if response := request(`{"method":"subscribe","params":{"transaction_ids":["0x0a"]}}`); response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+	if response := request(`{"method":"subscribe","params":{"transaction_ids":["0x0b"]}}`); response.Error == nil || !strings.Contains(response.Error.Message, "subscription limit") {
+		t.Fatal("Expected subscription limit error")
+	}
+	if response := request(`{"method":"unsubscribe","params":{"addresses":["15"]}}`); response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+	if response := request(`{"method":"subscribe","params":{"transaction_ids":["0x0b"]}}`); response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+}
+
+func TestWebSocketSameOrigin(t *testing.T) {
+	server := httptest.NewServer(NewHub())
+	defer server.Close()
+	wsURL := strings.Replace(server.URL, "http", "ws", 1)
+
+	if _, err := websocket.Dial(wsURL, "", "https://attacker.example"); err == nil {
+		t.Fatal("Expected a subscriber from another origin to be refused")
+	}
+
+	ws, err := websocket.Dial(wsURL, "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.Close()
+}
+
+func TestWebSocketServerTimeouts(t *testing.T) {
+	hub := NewHub()
+	server := httptest.NewUnstartedServer(hub)
+	server.Config.ReadTimeout = 100 * time.Millisecond
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	ws, err := websocket.Dial(strings.Replace(server.URL, "http", "ws", 1), "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// Subscribers stay connected once the timeouts of the HTTP server have expired
+	time.Sleep(300 * time.Millisecond)
+
+	if err := websocket.Message.Send(ws, `{"method":"subscribe","params":{"transaction_ids":["0x0a"]}}`); err != nil {
+		t.Fatal(err)
+	}
+	response := &query.Response{}
+	if err := websocket.JSON.Receive(ws, response); err != nil {
+		t.Fatal("Subscriber disconnected by the server timeouts: ", err)
+	}
+	if response.Error != nil {
+		t.Fatal("Unexpected error: ", response.Error.Message)
+	}
+}
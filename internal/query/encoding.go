@@ -0,0 +1,135 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// HexBytes is a byte slice encoded in JSON as a 0x prefixed hex string, used for transaction and block IDs
+type HexBytes []byte
+
+// MarshalJSON implements json.Marshaler
+func (b HexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0x" + hex.EncodeToString(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *HexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(s, "0x") {
+		return errors.New("hex string not prepended with '0x'")
+	}
+
+	decoded, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+	return nil
+}
+
+// Base58Bytes is a byte slice encoded in JSON as a base58 string, used for addresses and contract IDs
+type Base58Bytes []byte
+
+// MarshalJSON implements json.Marshaler
+func (b Base58Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base58.Encode(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Base58Bytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded := base58.Decode(s)
+	if len(decoded) == 0 && len(s) != 0 {
+		return errors.New("error decoding base58")
+	}
+
+	*b = decoded
+	return nil
+}
+
+// MarshalMessage encodes a protobuf message as JSON following the Koinos
+// conventions. Fields use their proto names and bytes fields are encoded as
+// hex or base58 according to their btype option, or as base64 otherwise.
+func MarshalMessage(m proto.Message) (json.RawMessage, error) {
+	return json.Marshal(messageValue(m.ProtoReflect()))
+}
+
+func messageValue(m protoreflect.Message) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			values := make([]interface{}, list.Len())
+			for i := range values {
+				values[i] = fieldValue(fd, list.Get(i))
+			}
+			result[string(fd.Name())] = values
+		case fd.IsMap():
+			values := make(map[string]interface{})
+			v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+				values[k.String()] = fieldValue(fd.MapValue(), mv)
+				return true
+			})
+			result[string(fd.Name())] = values
+		default:
+			result[string(fd.Name())] = fieldValue(fd, v)
+		}
+		return true
+	})
+
+	return result
+}
+
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageValue(v.Message())
+	case protoreflect.BytesKind:
+		return bytesValue(fd, v.Bytes())
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64 bit integers are strings, as in the protobuf JSON mapping
+		return v.String()
+	}
+
+	return v.Interface()
+}
+
+func bytesValue(fd protoreflect.FieldDescriptor, b []byte) string {
+	if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && proto.HasExtension(opts, koinos.E_Btype) {
+		switch proto.GetExtension(opts, koinos.E_Btype).(koinos.BytesType) {
+		case koinos.BytesType_HEX, koinos.BytesType_BLOCK_ID, koinos.BytesType_TRANSACTION_ID:
+			return "0x" + hex.EncodeToString(b)
+		case koinos.BytesType_BASE58, koinos.BytesType_CONTRACT_ID, koinos.BytesType_ADDRESS:
+			return base58.Encode(b)
+		}
+	}
+
+	return base64.URLEncoding.EncodeToString(b)
+}
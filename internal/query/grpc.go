@@ -0,0 +1,230 @@
+package query
+
+import (
+	"context"
+	"errors"
+
+	rpc "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/transaction_store"
+	"github.com/koinos/koinos-transaction-store/internal/query/querypb"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServiceName is the full name of the transaction_store_query service
+var GRPCServiceName = querypb.TransactionStoreQuery_ServiceDesc.ServiceName
+
+// NewGRPCServer creates a gRPC server serving the transaction_store_query
+// service, described in querypb/transaction_store_query.proto, from the given store
+func NewGRPCServer(store *trxstore.TransactionStore, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryErrorInterceptor),
+		grpc.ChainStreamInterceptor(streamErrorInterceptor))
+
+	server := grpc.NewServer(opts...)
+	querypb.RegisterTransactionStoreQueryServer(server, &grpcService{store: store})
+	return server
+}
+
+type grpcService struct {
+	querypb.UnimplementedTransactionStoreQueryServer
+	store *trxstore.TransactionStore
+}
+
+// unaryErrorInterceptor converts the store errors returned by unary methods to gRPC status errors
+func unaryErrorInterceptor(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	response, err := handler(ctx, request)
+	return response, grpcError(err)
+}
+
+// streamErrorInterceptor converts the store errors returned by streaming methods to gRPC status errors
+func streamErrorInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return grpcError(handler(srv, stream))
+}
+
+// grpcError converts a store error to a gRPC status error
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidParams), errors.Is(err, trxstore.ErrInvalidCursor), errors.Is(err, trxstore.ErrUnknownOperationType), errors.Is(err, trxstore.ErrInvalidTransactionID):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (s *grpcService) GetTransactionsById(ctx context.Context, request *rpc.GetTransactionsByIdRequest) (*rpc.GetTransactionsByIdResponse, error) {
+	items, err := s.store.GetTransactionsByID(request.TransactionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.GetTransactionsByIdResponse{Transactions: items}, nil
+}
+
+func (s *grpcService) GetTransactionsByNonce(ctx context.Context, request *querypb.GetTransactionsByNonceRequest) (*rpc.GetTransactionsByIdResponse, error) {
+	items, err := s.store.GetTransactionsByNonce(request.Account, request.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpc.GetTransactionsByIdResponse{Transactions: items}, nil
+}
+
+func (s *grpcService) GetTransactionsBySigner(request *querypb.GetTransactionsBySignerRequest, stream querypb.TransactionStoreQuery_GetTransactionsBySignerServer) error {
+	return streamPages(stream, request.Limit, request.Descending, func(page *trxstore.Pagination) ([]byte, error) {
+		result, err := s.store.GetTransactionsBySigner(request.Signer, page)
+		if err != nil {
+			return nil, err
+		}
+		return result.Cursor, sendAll(len(result.Transactions), func(i int) error { return stream.Send(result.Transactions[i]) })
+	})
+}
+
+func (s *grpcService) GetTransactionsByContractCall(request *querypb.GetTransactionsByContractCallRequest, stream querypb.TransactionStoreQuery_GetTransactionsByContractCallServer) error {
+	return streamPages(stream, request.Limit, request.Descending, func(page *trxstore.Pagination) ([]byte, error) {
+		result, err := s.store.GetTransactionsByContractCall(request.ContractId, request.EntryPoint, page)
+		if err != nil {
+			return nil, err
+		}
+		return result.Cursor, sendAll(len(result.Transactions), func(i int) error { return stream.Send(result.Transactions[i]) })
+	})
+}
+
+func (s *grpcService) GetTransactionsByOperationType(request *querypb.GetTransactionsByOperationTypeRequest, stream querypb.TransactionStoreQuery_GetTransactionsByOperationTypeServer) error {
+	opType, err := trxstore.ParseOperationType(request.OperationType)
+	if err != nil {
+		return err
+	}
+
+	return streamPages(stream, request.Limit, request.Descending, func(page *trxstore.Pagination) ([]byte, error) {
+		result, err := s.store.GetTransactionsByOperationType(opType, page)
+		if err != nil {
+			return nil, err
+		}
+		return result.Cursor, sendAll(len(result.Transactions), func(i int) error { return stream.Send(result.Transactions[i]) })
+	})
+}
+
+func (s *grpcService) GetEvents(request *querypb.GetEventsRequest, stream querypb.TransactionStoreQuery_GetEventsServer) error {
+	return streamPages(stream, request.Limit, request.Descending, func(page *trxstore.Pagination) ([]byte, error) {
+		result, err := s.store.GetEvents(request.Source, request.Name, page)
+		if err != nil {
+			return nil, err
+		}
+		return result.Cursor, sendAll(len(result.Events), func(i int) error {
+			return stream.Send(&querypb.IndexedEvent{TransactionId: result.Events[i].TransactionID, Event: result.Events[i].Event})
+		})
+	})
+}
+
+func (s *grpcService) GetNonces(request *querypb.GetNoncesRequest, stream querypb.TransactionStoreQuery_GetNoncesServer) error {
+	return streamPages(stream, request.Limit, request.Descending, func(page *trxstore.Pagination) ([]byte, error) {
+		result, err := s.store.GetNonces(request.Account, page)
+		if err != nil {
+			return nil, err
+		}
+		return result.Cursor, sendAll(len(result.Nonces), func(i int) error {
+			return stream.Send(&querypb.NonceEntry{Nonce: result.Nonces[i].Nonce, TransactionId: result.Nonces[i].TransactionID})
+		})
+	})
+}
+
+func (s *grpcService) GetTransactionSigners(ctx context.Context, request *querypb.GetTransactionSignersRequest) (*querypb.GetTransactionSignersResponse, error) {
+	signers, err := s.store.GetTransactionSigners(request.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &querypb.GetTransactionSignersResponse{Signers: signers}, nil
+}
+
+func (s *grpcService) GetResourceUsage(ctx context.Context, request *querypb.GetResourceUsageRequest) (*querypb.GetResourceUsageResponse, error) {
+	usage, err := getResourceUsage(s.store, &GetResourceUsageParams{
+		Account:     request.Account,
+		StartHeight: request.StartHeight,
+		EndHeight:   request.EndHeight,
+		StartTime:   request.StartTime,
+		EndTime:     request.EndTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := &trxstore.ResourceUsage{}
+	response := &querypb.GetResourceUsageResponse{Usage: make([]*querypb.ResourceUsageBucket, len(usage))}
+	for i, bucket := range usage {
+		total.Add(bucket)
+		response.Usage[i] = &querypb.ResourceUsageBucket{Height: bucket.Height, Usage: resourceUsageMessage(bucket)}
+	}
+	response.Total = resourceUsageMessage(total)
+
+	return response, nil
+}
+
+func resourceUsageMessage(usage *trxstore.ResourceUsage) *querypb.ResourceUsage {
+	return &querypb.ResourceUsage{
+		Transactions:         usage.Transactions,
+		RcUsed:               usage.RcUsed,
+		DiskStorageUsed:      usage.DiskStorageUsed,
+		NetworkBandwidthUsed: usage.NetworkBandwidthUsed,
+		ComputeBandwidthUsed: usage.ComputeBandwidthUsed,
+	}
+}
+
+// streamPages queries pages until the limit is reached or there are no more
+// results. Each page is a separate read of the store continuing from the cursor
+// of the previous one, so nothing is held open between pages and a stream may
+// include transactions added after it began.
+func streamPages(stream grpc.ServerStream, limit uint64, descending bool, queryPage func(page *trxstore.Pagination) ([]byte, error)) error {
+	page := &trxstore.Pagination{Descending: descending}
+	remaining := limit
+
+	for {
+		if err := stream.Context().Err(); err != nil {
+			return err
+		}
+
+		page.Limit = trxstore.MaxPageLimit
+		if limit > 0 && remaining < trxstore.MaxPageLimit {
+			page.Limit = uint32(remaining)
+		}
+
+		cursor, err := queryPage(page)
+		if err != nil {
+			return err
+		}
+
+		if cursor == nil {
+			return nil
+		}
+		if limit > 0 {
+			remaining -= uint64(page.Limit)
+			if remaining == 0 {
+				return nil
+			}
+		}
+		page.Cursor = cursor
+	}
+}
+
+func sendAll(n int, send func(i int) error) error {
+	for i := 0; i < n; i++ {
+		if err := send(i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
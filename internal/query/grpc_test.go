@@ -0,0 +1,193 @@
+package query
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	rpc "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/transaction_store"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"github.com/koinos/koinos-transaction-store/internal/query/querypb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// receiveAll calls recv until the end of a server stream
+func receiveAll(recv func() error) error {
+	for {
+		if err := recv(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// receiveItems receives every transaction of a server stream
+func receiveItems(stream interface {
+	Recv() (*transaction_store.TransactionItem, error)
+}) ([]*transaction_store.TransactionItem, error) {
+	items := make([]*transaction_store.TransactionItem, 0)
+	err := receiveAll(func() error {
+		item, err := stream.Recv()
+		if err == nil {
+			items = append(items, item)
+		}
+		return err
+	})
+	return items, err
+}
+
+func TestGRPC(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(makeStore(t))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	client := querypb.NewTransactionStoreQueryClient(conn)
+
+	response, err := client.GetTransactionsById(ctx, &rpc.GetTransactionsByIdRequest{TransactionIds: [][]byte{{0x12, 1}, {0x12, 9}}})
+	if err != nil {
+		t.Fatal("Error getting transactions: ", err)
+	}
+	if len(response.Transactions) != 1 {
+		t.Fatalf("Expected 1 transaction, got %v", len(response.Transactions))
+	}
+
+	response, err = client.GetTransactionsByNonce(ctx, &querypb.GetTransactionsByNonceRequest{Account: []byte{0, 1}, Nonce: 4})
+	if err != nil {
+		t.Fatal("Error getting transactions: ", err)
+	}
+	if len(response.Transactions) != 1 || response.Transactions[0].Transaction.Id[1] != 2 {
+		t.Fatal("Unexpected transactions: ", response.Transactions)
+	}
+
+	contractStream, err := client.GetTransactionsByContractCall(ctx, &querypb.GetTransactionsByContractCallRequest{ContractId: []byte{0, 9}, EntryPoint: 7, Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := receiveItems(contractStream)
+	if err != nil {
+		t.Fatal("Error streaming transactions: ", err)
+	}
+	if len(items) != 3 || items[0].Transaction.Id[1] != 3 {
+		t.Fatal("Unexpected transactions: ", items)
+	}
+
+	opStream, err := client.GetTransactionsByOperationType(ctx, &querypb.GetTransactionsByOperationTypeRequest{OperationType: "call_contract", Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err = receiveItems(opStream)
+	if err != nil {
+		t.Fatal("Error streaming transactions: ", err)
+	}
+	if len(items) != 2 || items[0].Transaction.Id[1] != 1 {
+		t.Fatal("Unexpected transactions: ", items)
+	}
+
+	signerStream, err := client.GetTransactionsBySigner(ctx, &querypb.GetTransactionsBySignerRequest{Signer: []byte{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err = receiveItems(signerStream)
+	if err != nil || len(items) != 0 {
+		t.Fatal("Expected no transactions: ", err)
+	}
+
+	eventStream, err := client.GetEvents(ctx, &querypb.GetEventsRequest{Source: []byte{0, 9}, Name: "transfer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make([]*querypb.IndexedEvent, 0)
+	err = receiveAll(func() error {
+		event, err := eventStream.Recv()
+		if err == nil {
+			events = append(events, event)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal("Error streaming events: ", err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %v", len(events))
+	}
+	if events[2].Event.Sequence != 3 || events[2].Event.Name != "transfer" || events[2].TransactionId[1] != 3 {
+		t.Fatal("Unexpected event: ", events[2])
+	}
+
+	nonceStream, err := client.GetNonces(ctx, &querypb.GetNoncesRequest{Account: []byte{0, 1}, Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonces := make([]*querypb.NonceEntry, 0)
+	err = receiveAll(func() error {
+		nonce, err := nonceStream.Recv()
+		if err == nil {
+			nonces = append(nonces, nonce)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal("Error streaming nonces: ", err)
+	}
+	if len(nonces) != 3 || nonces[0].Nonce != 6 {
+		t.Fatal("Unexpected nonces: ", nonces)
+	}
+
+	signers, err := client.GetTransactionSigners(ctx, &querypb.GetTransactionSignersRequest{TransactionId: []byte{0x12, 1}})
+	if err != nil {
+		t.Fatal("Error getting signers: ", err)
+	}
+	if len(signers.Signers) != 0 {
+		t.Fatal("Expected no signers, got: ", signers.Signers)
+	}
+
+	usage, err := client.GetResourceUsage(ctx, &querypb.GetResourceUsageRequest{Account: []byte{0, 1}})
+	if err != nil {
+		t.Fatal("Error getting resource usage: ", err)
+	}
+	if len(usage.Usage) != 1 || usage.Total.Transactions != 3 || usage.Total.RcUsed != 600 {
+		t.Fatal("Unexpected resource usage: ", usage)
+	}
+
+	usage, err = client.GetResourceUsage(ctx, &querypb.GetResourceUsageRequest{Account: []byte{0, 1}, StartTime: 1, EndTime: 500})
+	if err != nil {
+		t.Fatal("Error getting resource usage: ", err)
+	}
+	if usage.Total.Transactions != 3 {
+		t.Fatal("Expected the usage of the bucket containing the time range, got: ", usage)
+	}
+
+	opStream, err = client.GetTransactionsByOperationType(ctx, &querypb.GetTransactionsByOperationTypeRequest{OperationType: "transfer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = receiveItems(opStream); status.Code(err) != codes.InvalidArgument {
+		t.Fatal("Expected invalid argument, got: ", err)
+	}
+
+	_, err = client.GetTransactionsById(ctx, &rpc.GetTransactionsByIdRequest{TransactionIds: [][]byte{{0, 1}}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatal("Expected invalid argument for an auxiliary key, got: ", err)
+	}
+
+	err = conn.Invoke(ctx, "/"+GRPCServiceName+"/get_block", &rpc.GetTransactionsByIdRequest{}, response)
+	if status.Code(err) != codes.Unimplemented {
+		t.Fatal("Expected unimplemented, got: ", err)
+	}
+}
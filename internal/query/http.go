@@ -0,0 +1,75 @@
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+// maxRequestSize is the largest HTTP request body accepted
+const maxRequestSize = 1 << 20
+
+// ServeHTTP implements http.Handler, serving queries as JSON over HTTP.
+//
+// A request envelope, as accepted by Handle, may be POSTed to the root path.
+// Alternatively the params alone may be POSTed to the path naming the method,
+// such as /get_transactions_by_id.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeHTTPResponse(w, http.StatusMethodNotAllowed, &Response{Error: &Error{Message: "method not allowed"}})
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		writeHTTPResponse(w, http.StatusBadRequest, &Response{Error: &Error{Message: fmt.Sprintf("malformed request, %v", err)}})
+		return
+	}
+
+	request := &Request{}
+	if method := strings.Trim(r.URL.Path, "/"); len(method) > 0 {
+		request.Method = method
+		request.Params = body
+	} else if err := json.Unmarshal(body, request); err != nil {
+		writeHTTPResponse(w, http.StatusBadRequest, &Response{Error: &Error{Message: fmt.Sprintf("malformed request, %v", err)}})
+		return
+	}
+
+	result, err := h.Query(request.Method, request.Params)
+	if err != nil {
+		writeHTTPResponse(w, httpStatus(err), &Response{Error: &Error{Message: err.Error()}})
+		return
+	}
+
+	writeHTTPResponse(w, http.StatusOK, &Response{Result: result})
+}
+
+// httpStatus returns the HTTP status of a failed query
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrUnknownMethod):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidParams), errors.Is(err, trxstore.ErrInvalidCursor):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+func writeHTTPResponse(w http.ResponseWriter, status int, response *Response) {
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		status = http.StatusInternalServerError
+		responseBytes, _ = json.Marshal(&Response{Error: &Error{Message: err.Error()}})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(responseBytes)
+}
@@ -0,0 +1,72 @@
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTP(t *testing.T) {
+	server := httptest.NewServer(NewHandler(makeStore(t)))
+	defer server.Close()
+
+	post := func(path string, body string) (int, *Response) {
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.Header.Get("Content-Type") != "application/json" {
+			t.Fatal("Expected a JSON response")
+		}
+
+		response := &Response{}
+		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+			t.Fatal("Malformed response: ", err)
+		}
+		return resp.StatusCode, response
+	}
+
+	status, response := post("/", `{"method":"get_transactions_by_id","params":{"transaction_ids":["0x1201"]}}`)
+	if status != http.StatusOK || response.Error != nil {
+		t.Fatalf("Unexpected response %v: %+v", status, response)
+	}
+	if len(response.Result.(map[string]interface{})["transactions"].([]interface{})) != 1 {
+		t.Fatal("Expected a transaction")
+	}
+
+	status, response = post("/get_transactions_by_contract_call", `{"contract_id":"1A","entry_point":7}`)
+	if status != http.StatusOK || response.Error != nil {
+		t.Fatalf("Unexpected response %v: %+v", status, response)
+	}
+	if len(response.Result.(map[string]interface{})["transactions"].([]interface{})) != 3 {
+		t.Fatal("Expected 3 transactions")
+	}
+
+	for path, expected := range map[string]struct {
+		body   string
+		status int
+	}{
+		"/":                           {`{"method":`, http.StatusBadRequest},
+		"/get_block":                  {`{}`, http.StatusNotFound},
+		"/get_transactions_by_signer": {`{"signr":"1A"}`, http.StatusBadRequest},
+		"/get_nonces":                 {`{"account":"12","cursor":"0x01"}`, http.StatusBadRequest},
+	} {
+		status, response = post(path, expected.body)
+		if status != expected.status || response.Error == nil {
+			t.Fatalf("Expected status %v for %s, got %v", expected.status, path, status)
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/get_nonces")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatal("Expected method not allowed, got: ", resp.StatusCode)
+	}
+}
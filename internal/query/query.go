@@ -0,0 +1,399 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+// Query methods
+const (
+	GetTransactionsByIDMethod            = "get_transactions_by_id"
+	GetTransactionsBySignerMethod        = "get_transactions_by_signer"
+	GetTransactionsByContractCallMethod  = "get_transactions_by_contract_call"
+	GetTransactionsByOperationTypeMethod = "get_transactions_by_operation_type"
+	GetEventsMethod                      = "get_events"
+	GetTransactionsByNonceMethod         = "get_transactions_by_nonce"
+	GetNoncesMethod                      = "get_nonces"
+	GetResourceUsageMethod               = "get_resource_usage"
+)
+
+var (
+	// ErrUnknownMethod occurs when a request names a method that does not exist
+	ErrUnknownMethod = errors.New("unknown method")
+
+	// ErrInvalidParams occurs when the params of a request cannot be decoded
+	ErrInvalidParams = errors.New("invalid params")
+)
+
+// Request is a JSON encoded query
+type Request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON encoded query result. Exactly one of Result and Error is set.
+type Response struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  *Error      `json:"error,omitempty"`
+}
+
+// Error describes a failed query
+type Error struct {
+	Message string `json:"message"`
+}
+
+// PageParams selects a page of an index query
+type PageParams struct {
+	Cursor     HexBytes `json:"cursor,omitempty"`
+	Limit      uint32   `json:"limit,omitempty"`
+	Descending bool     `json:"descending,omitempty"`
+}
+
+// GetTransactionsByIDParams are the params of get_transactions_by_id
+type GetTransactionsByIDParams struct {
+	TransactionIDs []HexBytes `json:"transaction_ids"`
+}
+
+// GetTransactionsBySignerParams are the params of get_transactions_by_signer
+type GetTransactionsBySignerParams struct {
+	Signer Base58Bytes `json:"signer"`
+	PageParams
+}
+
+// GetTransactionsByContractCallParams are the params of get_transactions_by_contract_call
+type GetTransactionsByContractCallParams struct {
+	ContractID Base58Bytes `json:"contract_id"`
+	EntryPoint uint32      `json:"entry_point"`
+	PageParams
+}
+
+// GetTransactionsByOperationTypeParams are the params of get_transactions_by_operation_type
+type GetTransactionsByOperationTypeParams struct {
+	OperationType string `json:"operation_type"`
+	PageParams
+}
+
+// GetEventsParams are the params of get_events
+type GetEventsParams struct {
+	Source Base58Bytes `json:"source"`
+	Name   string      `json:"name"`
+	PageParams
+}
+
+// GetTransactionsByNonceParams are the params of get_transactions_by_nonce
+type GetTransactionsByNonceParams struct {
+	Account Base58Bytes `json:"account"`
+	Nonce   uint64      `json:"nonce,string"`
+}
+
+// GetNoncesParams are the params of get_nonces
+type GetNoncesParams struct {
+	Account Base58Bytes `json:"account"`
+	PageParams
+}
+
+// GetResourceUsageParams are the params of get_resource_usage. The range is either in block
+// heights or, when a time is set, in block timestamps in milliseconds. Ends are exclusive and an
+// end of zero leaves the range unbounded.
+type GetResourceUsageParams struct {
+	Account     Base58Bytes `json:"account"`
+	StartHeight uint64      `json:"start_height,string"`
+	EndHeight   uint64      `json:"end_height,string,omitempty"`
+	StartTime   uint64      `json:"start_time,string,omitempty"`
+	EndTime     uint64      `json:"end_time,string,omitempty"`
+}
+
+// TransactionsResult is the result of a query returning transactions
+type TransactionsResult struct {
+	Transactions []json.RawMessage `json:"transactions"`
+	Cursor       HexBytes          `json:"cursor,omitempty"`
+}
+
+// Event is an event and the ID of the transaction that emitted it
+type Event struct {
+	TransactionID HexBytes        `json:"transaction_id"`
+	Event         json.RawMessage `json:"event"`
+}
+
+// EventsResult is the result of a query returning events
+type EventsResult struct {
+	Events []*Event `json:"events"`
+	Cursor HexBytes `json:"cursor,omitempty"`
+}
+
+// Nonce is a nonce and the ID of the transaction that used it
+type Nonce struct {
+	Nonce         uint64   `json:"nonce,string"`
+	TransactionID HexBytes `json:"transaction_id"`
+}
+
+// NoncesResult is the result of get_nonces
+type NoncesResult struct {
+	Nonces []*Nonce `json:"nonces"`
+	Cursor HexBytes `json:"cursor,omitempty"`
+}
+
+// ResourceUsage is the resources used by an account
+type ResourceUsage struct {
+	Transactions         uint64 `json:"transactions,string"`
+	RcUsed               uint64 `json:"rc_used,string"`
+	DiskStorageUsed      uint64 `json:"disk_storage_used,string"`
+	NetworkBandwidthUsed uint64 `json:"network_bandwidth_used,string"`
+	ComputeBandwidthUsed uint64 `json:"compute_bandwidth_used,string"`
+}
+
+// ResourceUsageBucket is the resources used by an account in the bucket of blocks starting at Height
+type ResourceUsageBucket struct {
+	Height uint64 `json:"height,string"`
+	ResourceUsage
+}
+
+// ResourceUsageResult is the result of get_resource_usage
+type ResourceUsageResult struct {
+	Usage []*ResourceUsageBucket `json:"usage"`
+	Total *ResourceUsage         `json:"total"`
+}
+
+// Handler serves transaction store queries encoded as JSON
+type Handler struct {
+	store *trxstore.TransactionStore
+}
+
+// NewHandler creates a Handler serving queries from the given store
+func NewHandler(store *trxstore.TransactionStore) *Handler {
+	return &Handler{store: store}
+}
+
+// Handle runs a JSON encoded request and returns the JSON encoded response
+func (h *Handler) Handle(data []byte) []byte {
+	response := &Response{}
+
+	request := &Request{}
+	if err := json.Unmarshal(data, request); err != nil {
+		response.Error = &Error{Message: fmt.Sprintf("malformed request, %v", err)}
+	} else if result, err := h.Query(request.Method, request.Params); err != nil {
+		response.Error = &Error{Message: err.Error()}
+	} else {
+		response.Result = result
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		responseBytes, _ = json.Marshal(&Response{Error: &Error{Message: err.Error()}})
+	}
+
+	return responseBytes
+}
+
+// Query runs the named method with JSON encoded params and returns its result
+func (h *Handler) Query(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case GetTransactionsByIDMethod:
+		p := &GetTransactionsByIDParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		trxIDs := make([][]byte, len(p.TransactionIDs))
+		for i, id := range p.TransactionIDs {
+			trxIDs[i] = id
+		}
+		items, err := h.store.GetTransactionsByID(trxIDs)
+		if err != nil {
+			return nil, err
+		}
+		return transactionsResult(&trxstore.TransactionPage{Transactions: items})
+
+	case GetTransactionsBySignerMethod:
+		p := &GetTransactionsBySignerParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		page, err := h.store.GetTransactionsBySigner(p.Signer, p.pagination())
+		if err != nil {
+			return nil, err
+		}
+		return transactionsResult(page)
+
+	case GetTransactionsByContractCallMethod:
+		p := &GetTransactionsByContractCallParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		page, err := h.store.GetTransactionsByContractCall(p.ContractID, p.EntryPoint, p.pagination())
+		if err != nil {
+			return nil, err
+		}
+		return transactionsResult(page)
+
+	case GetTransactionsByOperationTypeMethod:
+		p := &GetTransactionsByOperationTypeParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		opType, err := trxstore.ParseOperationType(p.OperationType)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %v", ErrInvalidParams, err)
+		}
+		page, err := h.store.GetTransactionsByOperationType(opType, p.pagination())
+		if err != nil {
+			return nil, err
+		}
+		return transactionsResult(page)
+
+	case GetEventsMethod:
+		p := &GetEventsParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		page, err := h.store.GetEvents(p.Source, p.Name, p.pagination())
+		if err != nil {
+			return nil, err
+		}
+		return eventsResult(page)
+
+	case GetTransactionsByNonceMethod:
+		p := &GetTransactionsByNonceParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		items, err := h.store.GetTransactionsByNonce(p.Account, p.Nonce)
+		if err != nil {
+			return nil, err
+		}
+		return transactionsResult(&trxstore.TransactionPage{Transactions: items})
+
+	case GetNoncesMethod:
+		p := &GetNoncesParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		page, err := h.store.GetNonces(p.Account, p.pagination())
+		if err != nil {
+			return nil, err
+		}
+		result := &NoncesResult{Nonces: make([]*Nonce, len(page.Nonces)), Cursor: page.Cursor}
+		for i, entry := range page.Nonces {
+			result.Nonces[i] = &Nonce{Nonce: entry.Nonce, TransactionID: entry.TransactionID}
+		}
+		return result, nil
+
+	case GetResourceUsageMethod:
+		p := &GetResourceUsageParams{}
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+		usage, err := getResourceUsage(h.store, p)
+		if err != nil {
+			return nil, err
+		}
+		total := &trxstore.ResourceUsage{}
+		result := &ResourceUsageResult{Usage: make([]*ResourceUsageBucket, len(usage))}
+		for i, bucket := range usage {
+			total.Add(bucket)
+			result.Usage[i] = &ResourceUsageBucket{Height: bucket.Height, ResourceUsage: resourceUsage(bucket)}
+		}
+		totalUsage := resourceUsage(total)
+		result.Total = &totalUsage
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownMethod, method)
+}
+
+// getResourceUsage returns the resource usage in the range of the params, by height or by time
+func getResourceUsage(store *trxstore.TransactionStore, p *GetResourceUsageParams) ([]*trxstore.ResourceUsage, error) {
+	endHeight, endTime := p.EndHeight, p.EndTime
+	if endHeight == 0 {
+		endHeight = math.MaxUint64
+	}
+	if endTime == 0 {
+		endTime = math.MaxUint64
+	}
+
+	if p.StartTime > 0 || endTime < math.MaxUint64 {
+		return store.GetResourceUsageByTime(p.Account, p.StartTime, endTime)
+	}
+	return store.GetResourceUsage(p.Account, p.StartHeight, endHeight)
+}
+
+func (p *PageParams) pagination() *trxstore.Pagination {
+	return &trxstore.Pagination{
+		Cursor:     p.Cursor,
+		Limit:      p.Limit,
+		Descending: p.Descending,
+	}
+}
+
+// decodeParams decodes request params, rejecting unknown fields so that typos are not silently ignored
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%w, %v", ErrInvalidParams, err)
+	}
+
+	return nil
+}
+
+func transactionsResult(page *trxstore.TransactionPage) (*TransactionsResult, error) {
+	result := &TransactionsResult{
+		Transactions: make([]json.RawMessage, len(page.Transactions)),
+		Cursor:       page.Cursor,
+	}
+
+	for i, item := range page.Transactions {
+		itemJSON, err := marshalItem(item)
+		if err != nil {
+			return nil, err
+		}
+		result.Transactions[i] = itemJSON
+	}
+
+	return result, nil
+}
+
+func eventsResult(page *trxstore.EventPage) (*EventsResult, error) {
+	result := &EventsResult{
+		Events: make([]*Event, len(page.Events)),
+		Cursor: page.Cursor,
+	}
+
+	for i, event := range page.Events {
+		eventJSON, err := MarshalMessage(event.Event)
+		if err != nil {
+			return nil, fmt.Errorf("%w, %v", trxstore.ErrSerialization, err)
+		}
+		result.Events[i] = &Event{TransactionID: event.TransactionID, Event: eventJSON}
+	}
+
+	return result, nil
+}
+
+func resourceUsage(usage *trxstore.ResourceUsage) ResourceUsage {
+	return ResourceUsage{
+		Transactions:         usage.Transactions,
+		RcUsed:               usage.RcUsed,
+		DiskStorageUsed:      usage.DiskStorageUsed,
+		NetworkBandwidthUsed: usage.NetworkBandwidthUsed,
+		ComputeBandwidthUsed: usage.ComputeBandwidthUsed,
+	}
+}
+
+func marshalItem(item *transaction_store.TransactionItem) (json.RawMessage, error) {
+	itemJSON, err := MarshalMessage(item)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", trxstore.ErrSerialization, err)
+	}
+
+	return itemJSON, nil
+}
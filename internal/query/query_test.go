@@ -0,0 +1,210 @@
+package query
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+func makeStore(t *testing.T) *trxstore.TransactionStore {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend(), trxstore.WithEveryBlockTime(true))
+
+	for i := byte(1); i <= 3; i++ {
+		nonce, err := util.UInt64ToNonceBytes(uint64(i) * 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trx := &protocol.Transaction{
+			Id:     []byte{0x12, i},
+			Header: &protocol.TransactionHeader{Payer: []byte{0, 1}, RcLimit: uint64(i) * 1000, Nonce: nonce},
+			Operations: []*protocol.Operation{{
+				Op: &protocol.Operation_CallContract{
+					CallContract: &protocol.CallContractOperation{ContractId: []byte{0, 9}, EntryPoint: 7, Args: []byte{i}},
+				},
+			}},
+		}
+		receipt := &protocol.TransactionReceipt{
+			Id:     trx.Id,
+			Payer:  trx.Header.Payer,
+			RcUsed: uint64(i) * 100,
+			Events: []*protocol.EventData{{Sequence: uint32(i), Source: []byte{0, 9}, Name: "transfer", Impacted: [][]byte{{0, i}}}},
+		}
+		if err := store.AddIncludedTransactionWithReceipt(trx, receipt, &koinos.BlockTopology{Id: []byte{0x12, 0xff, i}, Height: uint64(i)}); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+		if err := store.PutBlockTime(uint64(i), uint64(i)*1000); err != nil {
+			t.Fatal("Error recording block time: ", err)
+		}
+	}
+
+	return store
+}
+
+func query(t *testing.T, handler *Handler, request string) (map[string]interface{}, string) {
+	response := &struct {
+		Result map[string]interface{} `json:"result"`
+		Error  *Error                 `json:"error"`
+	}{}
+	if err := json.Unmarshal(handler.Handle([]byte(request)), response); err != nil {
+		t.Fatal("Malformed response: ", err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.Message
+	}
+
+	return response.Result, ""
+}
+
+func TestQuery(t *testing.T) {
+	handler := NewHandler(makeStore(t))
+
+	result, errMsg := query(t, handler, `{"method":"get_transactions_by_id","params":{"transaction_ids":["0x1201","0x1204","0x1203"]}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	trxs := result["transactions"].([]interface{})
+	if len(trxs) != 2 {
+		t.Fatalf("Expected 2 transactions, got %v", len(trxs))
+	}
+
+	// Bytes are encoded according to their Koinos type
+	item := trxs[0].(map[string]interface{})
+	trx := item["transaction"].(map[string]interface{})
+	if trx["id"] != "0x1201" {
+		t.Fatal("Expected hex transaction id, got: ", trx["id"])
+	}
+	if trx["header"].(map[string]interface{})["payer"] != "12" {
+		t.Fatal("Expected base58 payer, got: ", trx["header"])
+	}
+	if trx["header"].(map[string]interface{})["rc_limit"] != "1000" {
+		t.Fatal("Expected string encoded rc limit, got: ", trx["header"])
+	}
+	call := trx["operations"].([]interface{})[0].(map[string]interface{})["call_contract"].(map[string]interface{})
+	if call["contract_id"] != "1A" || call["args"] != "AQ==" || call["entry_point"] != float64(7) {
+		t.Fatal("Unexpected call contract encoding: ", call)
+	}
+	if item["containing_blocks"].([]interface{})[0] != "0x12ff01" {
+		t.Fatal("Expected hex block id, got: ", item["containing_blocks"])
+	}
+
+	result, errMsg = query(t, handler, `{"method":"get_transactions_by_contract_call","params":{"contract_id":"1A","entry_point":7,"limit":2,"descending":true}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	trxs = result["transactions"].([]interface{})
+	if len(trxs) != 2 || trxs[0].(map[string]interface{})["transaction"].(map[string]interface{})["id"] != "0x1203" {
+		t.Fatal("Unexpected transactions: ", trxs)
+	}
+
+	cursor, ok := result["cursor"].(string)
+	if !ok {
+		t.Fatal("Expected a cursor")
+	}
+	result, errMsg = query(t, handler, `{"method":"get_transactions_by_contract_call","params":{"contract_id":"1A","entry_point":7,"limit":2,"descending":true,"cursor":"`+cursor+`"}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	trxs = result["transactions"].([]interface{})
+	if len(trxs) != 1 || trxs[0].(map[string]interface{})["transaction"].(map[string]interface{})["id"] != "0x1201" {
+		t.Fatal("Unexpected transactions: ", trxs)
+	}
+	if _, ok = result["cursor"]; ok {
+		t.Fatal("Expected no cursor on the last page")
+	}
+
+	result, errMsg = query(t, handler, `{"method":"get_transactions_by_signer","params":{"signer":"1A"}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	if len(result["transactions"].([]interface{})) != 0 {
+		t.Fatal("Expected no transactions")
+	}
+
+	result, errMsg = query(t, handler, `{"method":"get_transactions_by_operation_type","params":{"operation_type":"call_contract","limit":1}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	trxs = result["transactions"].([]interface{})
+	if len(trxs) != 1 || trxs[0].(map[string]interface{})["transaction"].(map[string]interface{})["id"] != "0x1201" {
+		t.Fatal("Unexpected transactions: ", trxs)
+	}
+
+	result, errMsg = query(t, handler, `{"method":"get_events","params":{"source":"1A","name":"transfer","descending":true}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	events := result["events"].([]interface{})
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %v", len(events))
+	}
+	event := events[0].(map[string]interface{})
+	if event["transaction_id"] != "0x1203" {
+		t.Fatal("Unexpected transaction id: ", event["transaction_id"])
+	}
+	eventData := event["event"].(map[string]interface{})
+	if eventData["sequence"] != float64(3) || eventData["source"] != "1A" || eventData["name"] != "transfer" || eventData["impacted"].([]interface{})[0] != "14" {
+		t.Fatal("Unexpected event encoding: ", eventData)
+	}
+
+	result, errMsg = query(t, handler, `{"method":"get_transactions_by_nonce","params":{"account":"12","nonce":"4"}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	trxs = result["transactions"].([]interface{})
+	if len(trxs) != 1 || trxs[0].(map[string]interface{})["transaction"].(map[string]interface{})["id"] != "0x1202" {
+		t.Fatal("Unexpected transactions: ", trxs)
+	}
+
+	result, errMsg = query(t, handler, `{"method":"get_nonces","params":{"account":"12","limit":2}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	nonces := result["nonces"].([]interface{})
+	if len(nonces) != 2 || nonces[1].(map[string]interface{})["nonce"] != "4" || nonces[1].(map[string]interface{})["transaction_id"] != "0x1202" || result["cursor"] == nil {
+		t.Fatal("Unexpected nonces: ", result)
+	}
+
+	result, errMsg = query(t, handler, `{"method":"get_resource_usage","params":{"account":"12","start_height":"2"}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	if len(result["usage"].([]interface{})) != 1 {
+		t.Fatal("Expected a single usage bucket: ", result)
+	}
+	total := result["total"].(map[string]interface{})
+	if total["transactions"] != "3" || total["rc_used"] != "600" {
+		t.Fatal("Unexpected total usage: ", total)
+	}
+	if _, ok = total["height"]; ok {
+		t.Fatal("Expected no height in total usage")
+	}
+
+	// Times are mapped to the buckets of the blocks recorded at those times
+	result, errMsg = query(t, handler, `{"method":"get_resource_usage","params":{"account":"12","start_time":"1500","end_time":"2500"}}`)
+	if len(errMsg) > 0 {
+		t.Fatal("Unexpected error: ", errMsg)
+	}
+	if usage := result["usage"].([]interface{}); len(usage) != 1 || usage[0].(map[string]interface{})["height"] != "0" {
+		t.Fatal("Expected the first usage bucket: ", result)
+	}
+
+	for request, expected := range map[string]string{
+		`{"method":"get_transactions_by_operation_type","params":{"operation_type":"transfer"}}`: "unknown operation type",
+		`{"method":"get_block"}`: "unknown method",
+		`{"method":"get_transactions_by_signer","params":{"signr":"1A"}}`:                    "invalid params",
+		`{"method":"get_transactions_by_id","params":{"transaction_ids":["1201"]}}`:          "invalid params",
+		`{"method":"get_transactions_by_signer","params":{"signer":"1A","cursor":"0x0102"}}`: "invalid cursor",
+		`{"method":`: "malformed request",
+	} {
+		_, errMsg = query(t, handler, request)
+		if !strings.Contains(errMsg, expected) {
+			t.Fatalf("Expected error containing '%s', got '%s'", expected, errMsg)
+		}
+	}
+}
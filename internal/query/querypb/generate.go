@@ -0,0 +1,6 @@
+// Package querypb holds the messages and service of transaction_store_query.proto,
+// generated with protoc-gen-go and protoc-gen-go-grpc. KOINOS_PROTO must name a
+// checkout of koinos-proto, which the koinos imports are resolved from.
+package querypb
+
+//go:generate protoc -I . -I $KOINOS_PROTO --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative transaction_store_query.proto
@@ -0,0 +1,543 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: transaction_store_query.proto
+
+package querypb
+
+import (
+	context "context"
+	transaction_store "github.com/koinos/koinos-proto-golang/v2/koinos/rpc/transaction_store"
+	transaction_store1 "github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TransactionStoreQuery_GetTransactionsById_FullMethodName            = "/koinos.transaction_store.query.transaction_store_query/get_transactions_by_id"
+	TransactionStoreQuery_GetTransactionsByNonce_FullMethodName         = "/koinos.transaction_store.query.transaction_store_query/get_transactions_by_nonce"
+	TransactionStoreQuery_GetTransactionsBySigner_FullMethodName        = "/koinos.transaction_store.query.transaction_store_query/get_transactions_by_signer"
+	TransactionStoreQuery_GetTransactionsByContractCall_FullMethodName  = "/koinos.transaction_store.query.transaction_store_query/get_transactions_by_contract_call"
+	TransactionStoreQuery_GetTransactionsByOperationType_FullMethodName = "/koinos.transaction_store.query.transaction_store_query/get_transactions_by_operation_type"
+	TransactionStoreQuery_GetEvents_FullMethodName                      = "/koinos.transaction_store.query.transaction_store_query/get_events"
+	TransactionStoreQuery_GetNonces_FullMethodName                      = "/koinos.transaction_store.query.transaction_store_query/get_nonces"
+	TransactionStoreQuery_GetTransactionSigners_FullMethodName          = "/koinos.transaction_store.query.transaction_store_query/get_transaction_signers"
+	TransactionStoreQuery_GetResourceUsage_FullMethodName               = "/koinos.transaction_store.query.transaction_store_query/get_resource_usage"
+)
+
+// TransactionStoreQueryClient is the client API for TransactionStoreQuery service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TransactionStoreQueryClient interface {
+	GetTransactionsById(ctx context.Context, in *transaction_store.GetTransactionsByIdRequest, opts ...grpc.CallOption) (*transaction_store.GetTransactionsByIdResponse, error)
+	GetTransactionsByNonce(ctx context.Context, in *GetTransactionsByNonceRequest, opts ...grpc.CallOption) (*transaction_store.GetTransactionsByIdResponse, error)
+	GetTransactionsBySigner(ctx context.Context, in *GetTransactionsBySignerRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetTransactionsBySignerClient, error)
+	GetTransactionsByContractCall(ctx context.Context, in *GetTransactionsByContractCallRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetTransactionsByContractCallClient, error)
+	GetTransactionsByOperationType(ctx context.Context, in *GetTransactionsByOperationTypeRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetTransactionsByOperationTypeClient, error)
+	GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetEventsClient, error)
+	GetNonces(ctx context.Context, in *GetNoncesRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetNoncesClient, error)
+	GetTransactionSigners(ctx context.Context, in *GetTransactionSignersRequest, opts ...grpc.CallOption) (*GetTransactionSignersResponse, error)
+	GetResourceUsage(ctx context.Context, in *GetResourceUsageRequest, opts ...grpc.CallOption) (*GetResourceUsageResponse, error)
+}
+
+type transactionStoreQueryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTransactionStoreQueryClient(cc grpc.ClientConnInterface) TransactionStoreQueryClient {
+	return &transactionStoreQueryClient{cc}
+}
+
+func (c *transactionStoreQueryClient) GetTransactionsById(ctx context.Context, in *transaction_store.GetTransactionsByIdRequest, opts ...grpc.CallOption) (*transaction_store.GetTransactionsByIdResponse, error) {
+	out := new(transaction_store.GetTransactionsByIdResponse)
+	err := c.cc.Invoke(ctx, TransactionStoreQuery_GetTransactionsById_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionStoreQueryClient) GetTransactionsByNonce(ctx context.Context, in *GetTransactionsByNonceRequest, opts ...grpc.CallOption) (*transaction_store.GetTransactionsByIdResponse, error) {
+	out := new(transaction_store.GetTransactionsByIdResponse)
+	err := c.cc.Invoke(ctx, TransactionStoreQuery_GetTransactionsByNonce_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionStoreQueryClient) GetTransactionsBySigner(ctx context.Context, in *GetTransactionsBySignerRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetTransactionsBySignerClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionStoreQuery_ServiceDesc.Streams[0], TransactionStoreQuery_GetTransactionsBySigner_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionStoreQueryGetTransactionsBySignerClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionStoreQuery_GetTransactionsBySignerClient interface {
+	Recv() (*transaction_store1.TransactionItem, error)
+	grpc.ClientStream
+}
+
+type transactionStoreQueryGetTransactionsBySignerClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionStoreQueryGetTransactionsBySignerClient) Recv() (*transaction_store1.TransactionItem, error) {
+	m := new(transaction_store1.TransactionItem)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transactionStoreQueryClient) GetTransactionsByContractCall(ctx context.Context, in *GetTransactionsByContractCallRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetTransactionsByContractCallClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionStoreQuery_ServiceDesc.Streams[1], TransactionStoreQuery_GetTransactionsByContractCall_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionStoreQueryGetTransactionsByContractCallClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionStoreQuery_GetTransactionsByContractCallClient interface {
+	Recv() (*transaction_store1.TransactionItem, error)
+	grpc.ClientStream
+}
+
+type transactionStoreQueryGetTransactionsByContractCallClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionStoreQueryGetTransactionsByContractCallClient) Recv() (*transaction_store1.TransactionItem, error) {
+	m := new(transaction_store1.TransactionItem)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transactionStoreQueryClient) GetTransactionsByOperationType(ctx context.Context, in *GetTransactionsByOperationTypeRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetTransactionsByOperationTypeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionStoreQuery_ServiceDesc.Streams[2], TransactionStoreQuery_GetTransactionsByOperationType_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionStoreQueryGetTransactionsByOperationTypeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionStoreQuery_GetTransactionsByOperationTypeClient interface {
+	Recv() (*transaction_store1.TransactionItem, error)
+	grpc.ClientStream
+}
+
+type transactionStoreQueryGetTransactionsByOperationTypeClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionStoreQueryGetTransactionsByOperationTypeClient) Recv() (*transaction_store1.TransactionItem, error) {
+	m := new(transaction_store1.TransactionItem)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transactionStoreQueryClient) GetEvents(ctx context.Context, in *GetEventsRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionStoreQuery_ServiceDesc.Streams[3], TransactionStoreQuery_GetEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionStoreQueryGetEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionStoreQuery_GetEventsClient interface {
+	Recv() (*IndexedEvent, error)
+	grpc.ClientStream
+}
+
+type transactionStoreQueryGetEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionStoreQueryGetEventsClient) Recv() (*IndexedEvent, error) {
+	m := new(IndexedEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transactionStoreQueryClient) GetNonces(ctx context.Context, in *GetNoncesRequest, opts ...grpc.CallOption) (TransactionStoreQuery_GetNoncesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TransactionStoreQuery_ServiceDesc.Streams[4], TransactionStoreQuery_GetNonces_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &transactionStoreQueryGetNoncesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TransactionStoreQuery_GetNoncesClient interface {
+	Recv() (*NonceEntry, error)
+	grpc.ClientStream
+}
+
+type transactionStoreQueryGetNoncesClient struct {
+	grpc.ClientStream
+}
+
+func (x *transactionStoreQueryGetNoncesClient) Recv() (*NonceEntry, error) {
+	m := new(NonceEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *transactionStoreQueryClient) GetTransactionSigners(ctx context.Context, in *GetTransactionSignersRequest, opts ...grpc.CallOption) (*GetTransactionSignersResponse, error) {
+	out := new(GetTransactionSignersResponse)
+	err := c.cc.Invoke(ctx, TransactionStoreQuery_GetTransactionSigners_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionStoreQueryClient) GetResourceUsage(ctx context.Context, in *GetResourceUsageRequest, opts ...grpc.CallOption) (*GetResourceUsageResponse, error) {
+	out := new(GetResourceUsageResponse)
+	err := c.cc.Invoke(ctx, TransactionStoreQuery_GetResourceUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TransactionStoreQueryServer is the server API for TransactionStoreQuery service.
+// All implementations must embed UnimplementedTransactionStoreQueryServer
+// for forward compatibility
+type TransactionStoreQueryServer interface {
+	GetTransactionsById(context.Context, *transaction_store.GetTransactionsByIdRequest) (*transaction_store.GetTransactionsByIdResponse, error)
+	GetTransactionsByNonce(context.Context, *GetTransactionsByNonceRequest) (*transaction_store.GetTransactionsByIdResponse, error)
+	GetTransactionsBySigner(*GetTransactionsBySignerRequest, TransactionStoreQuery_GetTransactionsBySignerServer) error
+	GetTransactionsByContractCall(*GetTransactionsByContractCallRequest, TransactionStoreQuery_GetTransactionsByContractCallServer) error
+	GetTransactionsByOperationType(*GetTransactionsByOperationTypeRequest, TransactionStoreQuery_GetTransactionsByOperationTypeServer) error
+	GetEvents(*GetEventsRequest, TransactionStoreQuery_GetEventsServer) error
+	GetNonces(*GetNoncesRequest, TransactionStoreQuery_GetNoncesServer) error
+	GetTransactionSigners(context.Context, *GetTransactionSignersRequest) (*GetTransactionSignersResponse, error)
+	GetResourceUsage(context.Context, *GetResourceUsageRequest) (*GetResourceUsageResponse, error)
+	mustEmbedUnimplementedTransactionStoreQueryServer()
+}
+
+// UnimplementedTransactionStoreQueryServer must be embedded to have forward compatible implementations.
+type UnimplementedTransactionStoreQueryServer struct {
+}
+
+func (UnimplementedTransactionStoreQueryServer) GetTransactionsById(context.Context, *transaction_store.GetTransactionsByIdRequest) (*transaction_store.GetTransactionsByIdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionsById not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetTransactionsByNonce(context.Context, *GetTransactionsByNonceRequest) (*transaction_store.GetTransactionsByIdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionsByNonce not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetTransactionsBySigner(*GetTransactionsBySignerRequest, TransactionStoreQuery_GetTransactionsBySignerServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetTransactionsBySigner not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetTransactionsByContractCall(*GetTransactionsByContractCallRequest, TransactionStoreQuery_GetTransactionsByContractCallServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetTransactionsByContractCall not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetTransactionsByOperationType(*GetTransactionsByOperationTypeRequest, TransactionStoreQuery_GetTransactionsByOperationTypeServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetTransactionsByOperationType not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetEvents(*GetEventsRequest, TransactionStoreQuery_GetEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetEvents not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetNonces(*GetNoncesRequest, TransactionStoreQuery_GetNoncesServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetNonces not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetTransactionSigners(context.Context, *GetTransactionSignersRequest) (*GetTransactionSignersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionSigners not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) GetResourceUsage(context.Context, *GetResourceUsageRequest) (*GetResourceUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResourceUsage not implemented")
+}
+func (UnimplementedTransactionStoreQueryServer) mustEmbedUnimplementedTransactionStoreQueryServer() {}
+
+// UnsafeTransactionStoreQueryServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TransactionStoreQueryServer will
+// result in compilation errors.
+type UnsafeTransactionStoreQueryServer interface {
+	mustEmbedUnimplementedTransactionStoreQueryServer()
+}
+
+func RegisterTransactionStoreQueryServer(s grpc.ServiceRegistrar, srv TransactionStoreQueryServer) {
+	s.RegisterService(&TransactionStoreQuery_ServiceDesc, srv)
+}
+
+func _TransactionStoreQuery_GetTransactionsById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(transaction_store.GetTransactionsByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionStoreQueryServer).GetTransactionsById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionStoreQuery_GetTransactionsById_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionStoreQueryServer).GetTransactionsById(ctx, req.(*transaction_store.GetTransactionsByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionStoreQuery_GetTransactionsByNonce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsByNonceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionStoreQueryServer).GetTransactionsByNonce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionStoreQuery_GetTransactionsByNonce_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionStoreQueryServer).GetTransactionsByNonce(ctx, req.(*GetTransactionsByNonceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionStoreQuery_GetTransactionsBySigner_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsBySignerRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionStoreQueryServer).GetTransactionsBySigner(m, &transactionStoreQueryGetTransactionsBySignerServer{stream})
+}
+
+type TransactionStoreQuery_GetTransactionsBySignerServer interface {
+	Send(*transaction_store1.TransactionItem) error
+	grpc.ServerStream
+}
+
+type transactionStoreQueryGetTransactionsBySignerServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionStoreQueryGetTransactionsBySignerServer) Send(m *transaction_store1.TransactionItem) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TransactionStoreQuery_GetTransactionsByContractCall_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsByContractCallRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionStoreQueryServer).GetTransactionsByContractCall(m, &transactionStoreQueryGetTransactionsByContractCallServer{stream})
+}
+
+type TransactionStoreQuery_GetTransactionsByContractCallServer interface {
+	Send(*transaction_store1.TransactionItem) error
+	grpc.ServerStream
+}
+
+type transactionStoreQueryGetTransactionsByContractCallServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionStoreQueryGetTransactionsByContractCallServer) Send(m *transaction_store1.TransactionItem) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TransactionStoreQuery_GetTransactionsByOperationType_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsByOperationTypeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionStoreQueryServer).GetTransactionsByOperationType(m, &transactionStoreQueryGetTransactionsByOperationTypeServer{stream})
+}
+
+type TransactionStoreQuery_GetTransactionsByOperationTypeServer interface {
+	Send(*transaction_store1.TransactionItem) error
+	grpc.ServerStream
+}
+
+type transactionStoreQueryGetTransactionsByOperationTypeServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionStoreQueryGetTransactionsByOperationTypeServer) Send(m *transaction_store1.TransactionItem) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TransactionStoreQuery_GetEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionStoreQueryServer).GetEvents(m, &transactionStoreQueryGetEventsServer{stream})
+}
+
+type TransactionStoreQuery_GetEventsServer interface {
+	Send(*IndexedEvent) error
+	grpc.ServerStream
+}
+
+type transactionStoreQueryGetEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionStoreQueryGetEventsServer) Send(m *IndexedEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TransactionStoreQuery_GetNonces_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetNoncesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionStoreQueryServer).GetNonces(m, &transactionStoreQueryGetNoncesServer{stream})
+}
+
+type TransactionStoreQuery_GetNoncesServer interface {
+	Send(*NonceEntry) error
+	grpc.ServerStream
+}
+
+type transactionStoreQueryGetNoncesServer struct {
+	grpc.ServerStream
+}
+
+func (x *transactionStoreQueryGetNoncesServer) Send(m *NonceEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TransactionStoreQuery_GetTransactionSigners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionSignersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionStoreQueryServer).GetTransactionSigners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionStoreQuery_GetTransactionSigners_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionStoreQueryServer).GetTransactionSigners(ctx, req.(*GetTransactionSignersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionStoreQuery_GetResourceUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResourceUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionStoreQueryServer).GetResourceUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionStoreQuery_GetResourceUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionStoreQueryServer).GetResourceUsage(ctx, req.(*GetResourceUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TransactionStoreQuery_ServiceDesc is the grpc.ServiceDesc for TransactionStoreQuery service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TransactionStoreQuery_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "koinos.transaction_store.query.transaction_store_query",
+	HandlerType: (*TransactionStoreQueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "get_transactions_by_id",
+			Handler:    _TransactionStoreQuery_GetTransactionsById_Handler,
+		},
+		{
+			MethodName: "get_transactions_by_nonce",
+			Handler:    _TransactionStoreQuery_GetTransactionsByNonce_Handler,
+		},
+		{
+			MethodName: "get_transaction_signers",
+			Handler:    _TransactionStoreQuery_GetTransactionSigners_Handler,
+		},
+		{
+			MethodName: "get_resource_usage",
+			Handler:    _TransactionStoreQuery_GetResourceUsage_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "get_transactions_by_signer",
+			Handler:       _TransactionStoreQuery_GetTransactionsBySigner_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "get_transactions_by_contract_call",
+			Handler:       _TransactionStoreQuery_GetTransactionsByContractCall_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "get_transactions_by_operation_type",
+			Handler:       _TransactionStoreQuery_GetTransactionsByOperationType_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "get_events",
+			Handler:       _TransactionStoreQuery_GetEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "get_nonces",
+			Handler:       _TransactionStoreQuery_GetNonces_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "transaction_store_query.proto",
+}
@@ -0,0 +1,181 @@
+package retention
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	log "github.com/koinos/koinos-log-golang/v2"
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+const (
+	defaultInterval  = time.Minute
+	defaultBatchSize = 1000
+)
+
+// Policy selects the transactions that are kept. A transaction is kept if either
+// limit keeps it, and reversible transactions are always kept.
+type Policy struct {
+	// Blocks keeps the transactions whose irreversible height is within this
+	// many blocks of the last irreversible block, 0 if transactions are not
+	// kept by height. A transaction is kept until the latest block including
+	// it, which is at or above its irreversible block, is old enough.
+	Blocks uint64
+
+	// Duration keeps the transactions included in blocks produced within this
+	// long, 0 if transactions are not kept by age. Unless the store records the
+	// time of every block, see trxstore.WithEveryBlockTime, transactions are
+	// kept back to the last recorded block produced before then.
+	Duration time.Duration
+}
+
+// Enabled returns true if the policy prunes any transactions
+func (policy Policy) Enabled() bool {
+	return policy.Blocks > 0 || policy.Duration > 0
+}
+
+// Option configures optional Pruner behavior
+type Option func(*Pruner)
+
+// WithInterval sets the time between pruning passes
+func WithInterval(interval time.Duration) Option {
+	return func(p *Pruner) {
+		p.interval = interval
+	}
+}
+
+// WithBatchSize sets the number of transactions pruned at a time
+func WithBatchSize(size int) Option {
+	return func(p *Pruner) {
+		p.batchSize = size
+	}
+}
+
+// withClock replaces the clock transaction ages are measured with
+func withClock(now func() time.Time) Option {
+	return func(p *Pruner) {
+		p.now = now
+	}
+}
+
+// Pruner deletes the transactions that are no longer kept by its policy in the background
+type Pruner struct {
+	store  *trxstore.TransactionStore
+	policy Policy
+
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+
+	// irreversibleHeight is the height of the last irreversible block, accessed atomically
+	irreversibleHeight uint64
+}
+
+// NewPruner creates a Pruner deleting transactions from the given store
+func NewPruner(store *trxstore.TransactionStore, policy Policy, opts ...Option) *Pruner {
+	p := &Pruner{
+		store:     store,
+		policy:    policy,
+		interval:  defaultInterval,
+		batchSize: defaultBatchSize,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// BlockIrreversible records that the given block has become irreversible
+func (p *Pruner) BlockIrreversible(topology *koinos.BlockTopology) {
+	for {
+		height := atomic.LoadUint64(&p.irreversibleHeight)
+		if topology.Height <= height || atomic.CompareAndSwapUint64(&p.irreversibleHeight, height, topology.Height) {
+			return
+		}
+	}
+}
+
+// Run prunes transactions every interval until the context is done
+func (p *Pruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			count, err := p.Prune(ctx)
+			if err != nil {
+				log.Warnf("Could not prune transactions: %s", err.Error())
+			}
+			if count > 0 {
+				log.Infof("Pruned %v transaction(s)", count)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Prune deletes every transaction that is not kept by the policy a batch at a time,
+// returning the number of transactions deleted
+func (p *Pruner) Prune(ctx context.Context) (uint64, error) {
+	height, err := p.pruneHeight()
+	if err != nil || height == 0 {
+		return 0, err
+	}
+
+	total := uint64(0)
+	for ctx.Err() == nil {
+		count, err := p.store.Prune(height, p.batchSize)
+		total += count
+		if err != nil || count < uint64(p.batchSize) {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// pruneHeight returns the height below which transactions are not kept
+func (p *Pruner) pruneHeight() (uint64, error) {
+	irreversible := atomic.LoadUint64(&p.irreversibleHeight)
+	if irreversible == 0 || !p.policy.Enabled() {
+		return 0, nil
+	}
+
+	height := irreversible
+	if p.policy.Blocks > 0 {
+		height = 0
+		if irreversible > p.policy.Blocks {
+			height = irreversible - p.policy.Blocks
+		}
+	}
+
+	if p.policy.Duration > 0 {
+		cutoff := p.now().Add(-p.policy.Duration)
+		if cutoff.Unix() <= 0 {
+			return 0, nil
+		}
+
+		// Every block below the last recorded one produced by the cutoff is older than it
+		byAge, err := p.store.HeightBefore(uint64(cutoff.UnixNano() / int64(time.Millisecond)))
+		if err != nil {
+			return 0, err
+		}
+
+		// A transaction kept by either limit is kept
+		if p.policy.Blocks == 0 || byAge < height {
+			height = byAge
+		}
+	}
+
+	if height > irreversible {
+		height = irreversible
+	}
+
+	return height, nil
+}
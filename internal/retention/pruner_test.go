@@ -0,0 +1,86 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+// makeStore returns a store with a transaction in each block from height 1 to 10,
+// produced a second apart, recording the times of the blocks at the given heights
+// or of every block if none are given
+func makeStore(t *testing.T, timedHeights ...uint64) *trxstore.TransactionStore {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend(), trxstore.WithEveryBlockTime(true))
+	for height := uint64(1); height <= 10; height++ {
+		trx := &protocol.Transaction{Id: []byte{byte(height)}}
+		if err := store.AddIncludedTransaction(trx, &koinos.BlockTopology{Id: []byte{byte(height)}, Height: height}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(timedHeights) == 0 {
+		for height := uint64(1); height <= 10; height++ {
+			timedHeights = append(timedHeights, height)
+		}
+	}
+	for _, height := range timedHeights {
+		if err := store.PutBlockTime(height, height*1000); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return store
+}
+
+func TestPruner(t *testing.T) {
+	cases := []struct {
+		name         string
+		policy       Policy
+		irreversible uint64
+		timedHeights []uint64
+		kept         int
+	}{
+		{"no irreversible block", Policy{Blocks: 3}, 0, nil, 10},
+		{"by height", Policy{Blocks: 3}, 8, nil, 6},
+		{"by age", Policy{Duration: 3 * time.Second}, 10, nil, 4},
+		{"by age, reversible", Policy{Duration: 3 * time.Second}, 5, nil, 6},
+		{"kept by height", Policy{Blocks: 5, Duration: 3 * time.Second}, 10, nil, 6},
+		{"kept by age", Policy{Blocks: 2, Duration: 3 * time.Second}, 10, nil, 4},
+		{"older than every block", Policy{Duration: time.Hour}, 10, nil, 10},
+		{"between recorded block times", Policy{Duration: 3 * time.Second}, 10, []uint64{1, 5, 9}, 6},
+	}
+
+	for _, c := range cases {
+		store := makeStore(t, c.timedHeights...)
+		pruner := NewPruner(store, c.policy, WithBatchSize(2), withClock(func() time.Time { return time.Unix(10, 0) }))
+		if c.irreversible > 0 {
+			pruner.BlockIrreversible(&koinos.BlockTopology{Height: c.irreversible})
+			pruner.BlockIrreversible(&koinos.BlockTopology{Height: c.irreversible - 1})
+		}
+
+		if _, err := pruner.Prune(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		ids := make([][]byte, 10)
+		for i := range ids {
+			ids[i] = []byte{byte(i + 1)}
+		}
+		items, err := store.GetTransactionsByID(ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != c.kept {
+			t.Fatalf("%s: expected %v kept transactions, got %v", c.name, c.kept, len(items))
+		}
+
+		// The oldest transactions are pruned
+		if len(items) > 0 && items[0].Transaction.Id[0] != byte(11-c.kept) {
+			t.Fatalf("%s: expected the first kept transaction to be %v, got %v", c.name, 11-c.kept, items[0].Transaction.Id[0])
+		}
+	}
+}
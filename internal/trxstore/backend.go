@@ -14,6 +14,68 @@ type TransactionStoreBackend interface {
 	 */
 	Get(key []byte) ([]byte, error)
 
+	/**
+	 * GetMany gets the values stored in several keys, reading them all from
+	 * the same snapshot. Values are returned in the order of the keys.
+	 *
+	 * A key that is not found has an empty value.
+	 */
+	GetMany(keys [][]byte) ([][]byte, error)
+
+	/**
+	 * GetManyWithPrefixes gets the values stored in several keys, as GetMany
+	 * does, and calls f for every stored key-value pair whose key begins with
+	 * one of the prefixes, reading them all from the same snapshot. Pairs are
+	 * visited prefix by prefix, in ascending key order, and f is given the
+	 * index of their prefix.
+	 *
+	 * Iteration stops at the first error returned by f, which is returned.
+	 */
+	GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error)
+
+	/**
+	 * Delete the value stored in the given key.
+	 *
+	 * Deleting a key that is not found is not an error.
+	 */
+	Delete(key []byte) error
+
+	/**
+	 * Iterate calls f for every stored key-value pair whose key begins with
+	 * prefix, in ascending key order. A nil prefix visits every pair.
+	 *
+	 * Iteration stops at the first error returned by f, which is returned.
+	 */
+	Iterate(prefix []byte, f func(key []byte, value []byte) error) error
+
+	/**
+	 * IterateFrom behaves like Iterate, but begins at start instead of the
+	 * first key with the prefix. Keys are visited in ascending order beginning
+	 * with the first key greater than or equal to start or, when reverse is set,
+	 * in descending order beginning with the last key less than or equal to
+	 * start. A nil start begins at the first, or last, key with the prefix.
+	 */
+	IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error
+
+	/**
+	 * NewWriteBatch creates a batch of writes that are only applied when it
+	 * is flushed. A batch is not atomic, a failed flush may have applied some
+	 * of its writes.
+	 */
+	NewWriteBatch() WriteBatch
+
 	// Resets the entire database
 	Reset() error
 }
+
+// WriteBatch buffers writes to a backend so that they are committed together
+type WriteBatch interface {
+	// Put adds a write of the given value to the given key to the batch
+	Put(key []byte, value []byte) error
+
+	// Flush applies every write in the batch, which may not be used afterwards
+	Flush() error
+
+	// Cancel discards a batch that has not been flushed
+	Cancel()
+}
@@ -2,6 +2,9 @@ package trxstore
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -160,3 +163,66 @@ func TestMapBackendBasic(t *testing.T) {
 		t.Errorf("expected empty slice")
 	}
 }
+
+func TestBackendGetMany(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		if err := b.Put([]byte("a"), []byte("1")); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Put([]byte("c"), []byte("3")); err != nil {
+			t.Fatal(err)
+		}
+
+		values, err := b.GetMany([][]byte{[]byte("c"), []byte("b"), []byte("a")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 3 || !bytes.Equal(values[0], []byte("3")) || len(values[1]) != 0 || !bytes.Equal(values[2], []byte("1")) {
+			t.Fatalf("Unexpected values %q", values)
+		}
+
+		if _, err := b.GetMany([][]byte{[]byte("a"), nil}); err == nil {
+			t.Error("expected error empty key")
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestBackendGetManyWithPrefixes(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		for _, key := range []string{"a", "ab2", "ab1", "b1", "c"} {
+			if err := b.Put([]byte(key), []byte("v"+key)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		visited := make([]string, 0)
+		values, err := b.GetManyWithPrefixes([][]byte{[]byte("c"), []byte("d")}, [][]byte{[]byte("b"), []byte("x"), []byte("ab")}, func(prefix int, key []byte, value []byte) error {
+			visited = append(visited, fmt.Sprintf("%v:%s=%s", prefix, key, value))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 2 || !bytes.Equal(values[0], []byte("vc")) || len(values[1]) != 0 {
+			t.Fatalf("Unexpected values %q", values)
+		}
+		expected := []string{"0:b1=vb1", "2:ab1=vab1", "2:ab2=vab2"}
+		if !reflect.DeepEqual(visited, expected) {
+			t.Fatalf("Expected %v, got %v", expected, visited)
+		}
+
+		stop := errors.New("stop")
+		_, err = b.GetManyWithPrefixes(nil, [][]byte{[]byte("a")}, func(prefix int, key []byte, value []byte) error {
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Fatal("Expected the error returned by f, got ", err)
+		}
+
+		CloseBackend(b)
+	}
+}
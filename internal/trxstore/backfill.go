@@ -0,0 +1,108 @@
+package trxstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+)
+
+// Transactions stored before they were indexed only have their record and the
+// IDs of the blocks including them. The migration to schema version 6 indexes
+// them at the heights of those blocks, which are looked up with a BlockResolver,
+// and stores the receipts those blocks hold, counting their resource usage.
+// Transactions whose blocks cannot be looked up are indexed at height 0, so that
+// they are listed and pruned before any other.
+
+// ResolvedBlock is a block including a transaction, as looked up by a BlockResolver
+type ResolvedBlock struct {
+	Height uint64
+
+	// Receipt is the receipt of the block, nil if it is not known
+	Receipt *protocol.BlockReceipt
+}
+
+// BlockResolver returns the blocks with the given IDs, in the same order, with nil for those that are not found
+type BlockResolver func(blockIDs [][]byte) ([]*ResolvedBlock, error)
+
+// migrateIndexes indexes a transaction that was stored before transactions were indexed
+func (handler *TransactionStore) migrateIndexes(key []byte, value []byte) error {
+	if isAuxiliaryKey(key) {
+		return nil
+	}
+
+	// Malformed records are left for Verify to report
+	record, err := handler.getIndexRecord(key)
+	if record != nil || errors.Is(err, ErrDeserialization) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	item := &transaction_store.TransactionItem{}
+	if err := unmarshalItem(value, item); err != nil || !bytes.Equal(item.GetTransaction().GetId(), key) {
+		return nil
+	}
+	if err := handler.assembleItem(item); err != nil {
+		return err
+	}
+
+	record, receipt, err := handler.resolveIndexRecord(item)
+	if err != nil {
+		return err
+	}
+
+	// The usage is counted before the receipt is stored so that it is not counted again, and the index
+	// record is stored last so that an interrupted migration indexes the transaction again
+	if receipt != nil {
+		if err := handler.addUsage(receipt, record.height); err != nil {
+			return err
+		}
+		if err := handler.putReceipt(receipt); err != nil {
+			return err
+		}
+	}
+
+	return handler.putIndexes(item.Transaction, receipt, record)
+}
+
+// resolveIndexRecord returns the index record of a transaction included in the blocks of the item,
+// with its receipt from the block that included it first, or nil if that block has none
+func (handler *TransactionStore) resolveIndexRecord(item *transaction_store.TransactionItem) (*indexRecord, *protocol.TransactionReceipt, error) {
+	record := &indexRecord{signers: recoverSigners(item.Transaction)}
+	if handler.resolveBlocks == nil || len(item.ContainingBlocks) == 0 {
+		return record, nil, nil
+	}
+
+	blocks, err := handler.resolveBlocks(item.ContainingBlocks)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w, %v", ErrBlockResolution, err)
+	}
+
+	var first *ResolvedBlock
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		if first == nil || block.Height < first.Height {
+			first = block
+		}
+		if block.Height > record.lastHeight {
+			record.lastHeight = block.Height
+		}
+	}
+	if first == nil {
+		return record, nil, nil
+	}
+
+	record.height = first.Height
+	for _, receipt := range first.Receipt.GetTransactionReceipts() {
+		if bytes.Equal(receipt.Id, item.Transaction.Id) {
+			return record, receipt, nil
+		}
+	}
+
+	return record, nil, nil
+}
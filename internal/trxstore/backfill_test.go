@@ -0,0 +1,150 @@
+package trxstore
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	util "github.com/koinos/koinos-util-golang/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMigrateIndexes(t *testing.T) {
+	alice, err := util.GenerateKoinosKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonce, err := util.UInt64ToNonceBytes(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contract := []byte{1, 2, 3}
+	trx := makeSignedTransaction(t, &protocol.TransactionHeader{Payer: alice.AddressBytes(), Nonce: nonce}, alice)
+	trx.Operations = []*protocol.Operation{{Op: &protocol.Operation_CallContract{CallContract: &protocol.CallContractOperation{ContractId: contract, EntryPoint: 7}}}}
+	orphan := &protocol.Transaction{Id: []byte{9}}
+
+	receipt := &protocol.TransactionReceipt{Id: trx.Id, Payer: alice.AddressBytes(), RcUsed: 5, Events: []*protocol.EventData{{Source: contract, Name: "transfer"}}}
+	blocks := map[string]*ResolvedBlock{
+		string([]byte{1}): {Height: 10, Receipt: &protocol.BlockReceipt{TransactionReceipts: []*protocol.TransactionReceipt{receipt}}},
+		string([]byte{2}): {Height: 20},
+	}
+	resolve := func(blockIDs [][]byte) ([]*ResolvedBlock, error) {
+		resolved := make([]*ResolvedBlock, len(blockIDs))
+		for i, blockID := range blockIDs {
+			resolved[i] = blocks[string(blockID)]
+		}
+		return resolved, nil
+	}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b, WithBlockResolver(resolve))
+
+		// An unversioned database written before transactions were indexed
+		for _, record := range []*transaction_store.TransactionItem{
+			{Transaction: trx, ContainingBlocks: [][]byte{{1}, {2}}},
+			{Transaction: orphan, ContainingBlocks: [][]byte{{3}}},
+		} {
+			recordBytes, err := proto.Marshal(record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Put(record.Transaction.Id, recordBytes); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// A migration that cannot look up blocks fails, and is resumed once they can be
+		failing := NewTransactionStore(b, WithBlockResolver(func([][]byte) ([]*ResolvedBlock, error) {
+			return nil, errors.New("block store unavailable")
+		}))
+		if err := failing.Migrate(nil); !errors.Is(err, ErrBlockResolution) {
+			t.Fatalf("Expected %v, got %v", ErrBlockResolution, err)
+		}
+		if err := store.Migrate(nil); err != nil {
+			t.Fatal(err)
+		}
+		checkSchemaVersion(t, store, SchemaVersion)
+
+		// The transaction is indexed at the height of the first block including it, with its receipt
+		page, err := store.GetTransactionsBySigner(alice.AddressBytes(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPage(t, page, trx)
+		if page, err = store.GetTransactionsByContractCall(contract, 7, nil); err != nil {
+			t.Fatal(err)
+		}
+		checkPage(t, page, trx)
+		if page, err = store.GetTransactionsByOperationType(CallContractOperation, nil); err != nil {
+			t.Fatal(err)
+		}
+		checkPage(t, page, trx)
+
+		items, err := store.GetTransactionsByNonce(alice.AddressBytes(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || !bytes.Equal(items[0].Transaction.Id, trx.Id) {
+			t.Fatalf("Expected the transaction by nonce, got %v", items)
+		}
+
+		events, err := store.GetEvents(contract, "transfer", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events.Events) != 1 || !bytes.Equal(events.Events[0].TransactionID, trx.Id) {
+			t.Fatalf("Expected the event of the transaction, got %v", events.Events)
+		}
+
+		usage, err := store.GetResourceUsage(alice.AddressBytes(), 0, UsageBucketSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(usage) != 1 || usage[0].Transactions != 1 || usage[0].RcUsed != 5 {
+			t.Fatalf("Unexpected usage %+v", usage)
+		}
+
+		record, err := store.getIndexRecord(trx.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.height != 10 || record.retainedHeight() != 20 {
+			t.Fatalf("Expected the transaction at 10 retained until 20, got %+v", record)
+		}
+
+		// Migrating again indexes nothing twice
+		if err := store.putSchemaVersion(SchemaVersion - 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Migrate(nil); err != nil {
+			t.Fatal(err)
+		}
+		if usage, err = store.GetResourceUsage(alice.AddressBytes(), 0, UsageBucketSize); err != nil {
+			t.Fatal(err)
+		}
+		if len(usage) != 1 || usage[0].Transactions != 1 {
+			t.Fatalf("Unexpected usage after migrating again %+v", usage)
+		}
+
+		// A transaction whose blocks are unknown is pruned first, the other once its last block is pruned
+		for _, prune := range []struct {
+			height   uint64
+			expected uint64
+		}{{1, 1}, {20, 0}, {21, 1}} {
+			count, err := store.Prune(prune.height, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != prune.expected {
+				t.Fatalf("Expected %v transactions pruned below %v, got %v", prune.expected, prune.height, count)
+			}
+		}
+
+		CloseBackend(b)
+	}
+}
@@ -1,8 +1,10 @@
 package trxstore
 
 import (
+	"bytes"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/dgraph-io/badger/v3"
 	"go.uber.org/zap"
@@ -15,8 +17,66 @@ type BadgerBackend struct {
 
 // NewBadgerBackend BadgerBackend constructor
 func NewBadgerBackend(opts badger.Options) *BadgerBackend {
-	badgerDB, _ := badger.Open(opts)
-	return &BadgerBackend{DB: badgerDB}
+	backend, _ := OpenBadgerBackend(opts)
+	return backend
+}
+
+// OpenBadgerBackend opens a BadgerBackend, returning an error if the database cannot be
+// opened, for instance because it is encrypted with a different key
+func OpenBadgerBackend(opts badger.Options) (*BadgerBackend, error) {
+	badgerDB, err := badger.Open(opts)
+	return &BadgerBackend{DB: badgerDB}, err
+}
+
+// WithBadgerEncryption returns opts encrypting the database at rest with key. Badger
+// encrypts data with data keys it rotates after rotation, which are themselves
+// encrypted with key.
+func WithBadgerEncryption(opts badger.Options, key []byte, rotation time.Duration) (badger.Options, error) {
+	if err := checkEncryptionKey(key); err != nil {
+		return opts, err
+	}
+
+	opts.EncryptionKey = key
+	if rotation > 0 {
+		opts.EncryptionKeyRotationDuration = rotation
+	}
+
+	// Encrypted tables must be decrypted to be read, caching their indexes avoids doing so on every read
+	if opts.IndexCacheSize == 0 {
+		opts.IndexCacheSize = badgerEncryptedIndexCacheSize
+	}
+
+	return opts, nil
+}
+
+// badgerEncryptedIndexCacheSize is the size of the index cache of encrypted databases in bytes
+const badgerEncryptedIndexCacheSize = 100 << 20
+
+// RotateBadgerEncryptionKey re-encrypts the data keys of the closed database in dir, encrypted
+// with oldKey, with newKey. An empty oldKey encrypts an unencrypted database from then on, an
+// empty newKey stops encrypting it. Data written before is only rewritten by compaction.
+func RotateBadgerEncryptionKey(dir string, oldKey []byte, newKey []byte) error {
+	for _, key := range [][]byte{oldKey, newKey} {
+		if len(key) > 0 {
+			if err := checkEncryptionKey(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	opts := badger.KeyRegistryOptions{
+		Dir:           dir,
+		ReadOnly:      true,
+		EncryptionKey: oldKey,
+	}
+	registry, err := badger.OpenKeyRegistry(opts)
+	if err != nil {
+		return err
+	}
+	defer registry.Close()
+
+	opts.EncryptionKey = newKey
+	return badger.WriteKeyRegistry(registry, opts)
 }
 
 // Close cleans backend resources
@@ -39,6 +99,37 @@ func (backend *BadgerBackend) Put(key, value []byte) error {
 	})
 }
 
+// Delete backend deleter
+func (backend *BadgerBackend) Delete(key []byte) error {
+	return backend.DB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
+// NewWriteBatch creates a batch committed with a Badger WriteBatch
+func (backend *BadgerBackend) NewWriteBatch() WriteBatch {
+	return &badgerWriteBatch{batch: backend.DB.NewWriteBatch()}
+}
+
+type badgerWriteBatch struct {
+	batch *badger.WriteBatch
+}
+
+func (wb *badgerWriteBatch) Put(key []byte, value []byte) error {
+	if value == nil {
+		return errors.New("Cannot put a nil value")
+	}
+	return wb.batch.Set(key, value)
+}
+
+func (wb *badgerWriteBatch) Flush() error {
+	return wb.batch.Flush()
+}
+
+func (wb *badgerWriteBatch) Cancel() {
+	wb.batch.Cancel()
+}
+
 // Get backend getter
 func (backend *BadgerBackend) Get(key []byte) ([]byte, error) {
 	var value []byte = nil
@@ -60,6 +151,124 @@ func (backend *BadgerBackend) Get(key []byte) ([]byte, error) {
 	return value, err
 }
 
+// GetMany backend getter reading every key in one transaction
+func (backend *BadgerBackend) GetMany(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	err := backend.DB.View(func(txn *badger.Txn) error {
+		for i, key := range keys {
+			item, err := txn.Get(key)
+			if err == badger.ErrKeyNotFound {
+				values[i] = make([]byte, 0)
+				continue
+			} else if err != nil {
+				return err
+			}
+			if values[i], err = item.ValueCopy(nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// GetManyWithPrefixes backend getter reading every key and every pair with the given prefixes in one transaction
+func (backend *BadgerBackend) GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	err := backend.DB.View(func(txn *badger.Txn) error {
+		for i, key := range keys {
+			item, err := txn.Get(key)
+			if err == badger.ErrKeyNotFound {
+				values[i] = make([]byte, 0)
+				continue
+			} else if err != nil {
+				return err
+			}
+			if values[i], err = item.ValueCopy(nil); err != nil {
+				return err
+			}
+		}
+
+		if len(prefixes) == 0 {
+			return nil
+		}
+
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for i, prefix := range prefixes {
+			for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+				item := it.Item()
+				value, err := item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				if err = f(i, item.KeyCopy(nil), value); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Iterate backend iterator
+func (backend *BadgerBackend) Iterate(prefix []byte, f func(key []byte, value []byte) error) error {
+	return backend.IterateFrom(prefix, nil, false, f)
+}
+
+// IterateFrom backend iterator beginning at the given key
+func (backend *BadgerBackend) IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error {
+	return backend.DB.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = reverse
+		if !reverse {
+			// A reverse seek may need to step over the key following the prefix
+			opts.Prefix = prefix
+		}
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		if start != nil {
+			it.Seek(start)
+		} else if !reverse {
+			it.Seek(prefix)
+		} else if end := prefixEnd(prefix); end != nil {
+			// Seeking in reverse lands on the last key less than or equal to end,
+			// which is only outside of the prefix if it is end itself
+			it.Seek(end)
+			if it.Valid() && bytes.Equal(it.Item().Key(), end) {
+				it.Next()
+			}
+		} else {
+			it.Rewind()
+		}
+
+		for ; it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if err = f(item.KeyCopy(nil), value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 // KoinosBadgerLogger implements the badger.Logger interface in roder to pass badger logs the the koinos logger
 type KoinosBadgerLogger struct {
 }
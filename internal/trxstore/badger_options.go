@@ -0,0 +1,118 @@
+package trxstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/options"
+)
+
+// Badger option profiles
+const (
+	// BadgerProfileDefault uses Badger's default options
+	BadgerProfileDefault = "default"
+
+	// BadgerProfileLowMemory keeps memory tables and caches small, at the cost of throughput
+	BadgerProfileLowMemory = "low-memory"
+
+	// BadgerProfileHighThroughput trades memory for faster ingestion and reads
+	BadgerProfileHighThroughput = "high-throughput"
+)
+
+// ErrUnknownBadgerProfile occurs when a Badger option profile does not exist
+var ErrUnknownBadgerProfile = errors.New("unknown badger profile")
+
+// badgerProfiles adjust Badger's default options
+var badgerProfiles = map[string]func(opts badger.Options) badger.Options{
+	BadgerProfileDefault: func(opts badger.Options) badger.Options {
+		return opts
+	},
+	BadgerProfileLowMemory: func(opts badger.Options) badger.Options {
+		opts.MemTableSize = 16 << 20
+		opts.NumMemtables = 2
+		opts.NumLevelZeroTables = 2
+		opts.NumLevelZeroTablesStall = 8
+		opts.BlockCacheSize = 32 << 20
+		opts.IndexCacheSize = 16 << 20
+		opts.NumCompactors = 2
+		// Values outside of the LSM tree keep the tables, and their cached blocks, small
+		opts.ValueThreshold = 1 << 10
+		return opts
+	},
+	BadgerProfileHighThroughput: func(opts badger.Options) badger.Options {
+		opts.MemTableSize = 256 << 20
+		opts.NumLevelZeroTables = 10
+		opts.NumLevelZeroTablesStall = 30
+		opts.BlockCacheSize = 1 << 30
+		opts.IndexCacheSize = 512 << 20
+		opts.NumCompactors = 8
+		return opts
+	},
+}
+
+// BadgerTuning selects a profile of Badger options and overrides some of them.
+// Options that are zero, or empty, keep the value of the profile.
+type BadgerTuning struct {
+	Profile string
+
+	MemTableSize   int64
+	BlockCacheSize int64
+	IndexCacheSize int64
+	ValueThreshold int64
+	NumCompactors  int
+
+	// Compression is the name of the compression of Badger's tables, see ParseCompression
+	Compression string
+
+	SyncWrites bool
+}
+
+// Apply returns opts adjusted by the profile and overrides of the tuning
+func (tuning *BadgerTuning) Apply(opts badger.Options) (badger.Options, error) {
+	profile := tuning.Profile
+	if len(profile) == 0 {
+		profile = BadgerProfileDefault
+	}
+
+	adjust, ok := badgerProfiles[profile]
+	if !ok {
+		return opts, fmt.Errorf("%w '%s'", ErrUnknownBadgerProfile, profile)
+	}
+	opts = adjust(opts)
+
+	if tuning.MemTableSize > 0 {
+		opts.MemTableSize = tuning.MemTableSize
+	}
+	if tuning.BlockCacheSize > 0 {
+		opts.BlockCacheSize = tuning.BlockCacheSize
+	}
+	if tuning.IndexCacheSize > 0 {
+		opts.IndexCacheSize = tuning.IndexCacheSize
+	}
+	if tuning.ValueThreshold > 0 {
+		opts.ValueThreshold = tuning.ValueThreshold
+	}
+	if tuning.NumCompactors > 0 {
+		opts.NumCompactors = tuning.NumCompactors
+	}
+
+	if len(tuning.Compression) > 0 {
+		compression, err := ParseCompression(tuning.Compression)
+		if err != nil {
+			return opts, err
+		}
+		switch compression {
+		case CompressionNone:
+			opts.Compression = options.None
+		case CompressionSnappy:
+			opts.Compression = options.Snappy
+		case CompressionZstd:
+			opts.Compression = options.ZSTD
+		}
+	}
+
+	opts.SyncWrites = opts.SyncWrites || tuning.SyncWrites
+
+	return opts, nil
+}
@@ -0,0 +1,93 @@
+package trxstore
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/dgraph-io/badger/v3/options"
+)
+
+func TestBadgerTuning(t *testing.T) {
+	defaults := badger.DefaultOptions("")
+
+	opts, err := (&BadgerTuning{}).Apply(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.MemTableSize != defaults.MemTableSize || opts.BlockCacheSize != defaults.BlockCacheSize || opts.Compression != defaults.Compression {
+		t.Fatal("Expected the default options")
+	}
+
+	opts, err = (&BadgerTuning{Profile: BadgerProfileLowMemory}).Apply(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.MemTableSize >= defaults.MemTableSize || opts.BlockCacheSize >= defaults.BlockCacheSize {
+		t.Fatalf("Expected smaller tables and caches, got %v and %v", opts.MemTableSize, opts.BlockCacheSize)
+	}
+
+	opts, err = (&BadgerTuning{Profile: BadgerProfileHighThroughput}).Apply(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.MemTableSize <= defaults.MemTableSize || opts.NumCompactors <= defaults.NumCompactors {
+		t.Fatalf("Expected larger tables and more compactors, got %v and %v", opts.MemTableSize, opts.NumCompactors)
+	}
+
+	// Overrides take precedence over the profile
+	tuning := &BadgerTuning{
+		Profile:        BadgerProfileLowMemory,
+		MemTableSize:   32 << 20,
+		BlockCacheSize: 64 << 20,
+		IndexCacheSize: 8 << 20,
+		ValueThreshold: 512,
+		NumCompactors:  3,
+		Compression:    "zstd",
+		SyncWrites:     true,
+	}
+	opts, err = tuning.Apply(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.MemTableSize != tuning.MemTableSize || opts.BlockCacheSize != tuning.BlockCacheSize || opts.IndexCacheSize != tuning.IndexCacheSize ||
+		opts.ValueThreshold != tuning.ValueThreshold || opts.NumCompactors != tuning.NumCompactors || opts.Compression != options.ZSTD || !opts.SyncWrites {
+		t.Fatalf("Expected the overridden options, got %+v", opts)
+	}
+
+	if _, err := (&BadgerTuning{Profile: "huge"}).Apply(defaults); !errors.Is(err, ErrUnknownBadgerProfile) {
+		t.Fatalf("Expected %v, got %v", ErrUnknownBadgerProfile, err)
+	}
+	if _, err := (&BadgerTuning{Compression: "lz4"}).Apply(defaults); !errors.Is(err, ErrUnknownCompression) {
+		t.Fatalf("Expected %v, got %v", ErrUnknownCompression, err)
+	}
+}
+
+func TestBadgerProfiles(t *testing.T) {
+	for profile := range badgerProfiles {
+		dir, err := os.MkdirTemp(os.TempDir(), "trxstore-test-*")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		opts, err := (&BadgerTuning{Profile: profile}).Apply(badger.DefaultOptions(dir).WithLogger(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := OpenBadgerBackend(opts)
+		if err != nil {
+			t.Fatalf("Could not open a database with the %s profile, %v", profile, err)
+		}
+		if err := b.Put([]byte("a"), make([]byte, 2048)); err != nil {
+			t.Fatal(err)
+		}
+		if value, err := b.Get([]byte("a")); err != nil || len(value) != 2048 {
+			t.Fatalf("Unexpected value of %v bytes, %v", len(value), err)
+		}
+
+		b.Close()
+		os.RemoveAll(dir)
+	}
+}
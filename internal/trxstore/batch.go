@@ -0,0 +1,205 @@
+package trxstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+)
+
+// IncludedTransaction is a transaction included in a block, with the receipt of its execution
+type IncludedTransaction struct {
+	Transaction *protocol.Transaction
+
+	// Receipt may be nil
+	Receipt  *protocol.TransactionReceipt
+	Topology *koinos.BlockTopology
+}
+
+var errNotSupportedInBatch = errors.New("not supported while staging a batch")
+
+// overlayBackend stages writes in memory on top of a backend, so that the records
+// of many transactions can be built with the usual read-modify-write code and
+// then committed together.
+type overlayBackend struct {
+	backend TransactionStoreBackend
+	pending map[string][]byte
+
+	// keys are the staged keys in the order they were first written
+	keys [][]byte
+}
+
+func (o *overlayBackend) Put(key []byte, value []byte) error {
+	if _, ok := o.pending[string(key)]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.pending[string(key)] = value
+	return nil
+}
+
+func (o *overlayBackend) Get(key []byte) ([]byte, error) {
+	if value, ok := o.pending[string(key)]; ok {
+		return value, nil
+	}
+	return o.backend.Get(key)
+}
+
+func (o *overlayBackend) GetMany(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		var err error
+		if values[i], err = o.Get(key); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// GetManyWithPrefixes reads the keys and prefixes one at a time, staged records do not change while a batch is staged
+func (o *overlayBackend) GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error) {
+	values, err := o.GetMany(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, prefix := range prefixes {
+		err := o.Iterate(prefix, func(key []byte, value []byte) error {
+			return f(i, key, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (o *overlayBackend) Delete(key []byte) error {
+	return errNotSupportedInBatch
+}
+
+func (o *overlayBackend) Iterate(prefix []byte, f func(key []byte, value []byte) error) error {
+	return o.IterateFrom(prefix, nil, false, f)
+}
+
+// IterateFrom merges the staged records with the prefix into those of the backend. It is
+// meant for the small ranges read while staging, every record in the range is held in memory.
+func (o *overlayBackend) IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error {
+	records := make(map[string][]byte)
+	err := o.backend.IterateFrom(prefix, start, reverse, func(key []byte, value []byte) error {
+		records[string(key)] = value
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range o.keys {
+		if !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+		if start != nil && ((!reverse && bytes.Compare(key, start) < 0) || (reverse && bytes.Compare(key, start) > 0)) {
+			continue
+		}
+		records[string(key)] = o.pending[string(key)]
+	}
+
+	keys := make([]string, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	if reverse {
+		sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	} else {
+		sort.Strings(keys)
+	}
+
+	for _, key := range keys {
+		if err := f([]byte(key), records[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *overlayBackend) NewWriteBatch() WriteBatch {
+	return o.backend.NewWriteBatch()
+}
+
+func (o *overlayBackend) Reset() error {
+	return errNotSupportedInBatch
+}
+
+// AddIncludedTransactions adds many included transactions, as AddIncludedTransactionWithReceipt
+// does, committing their records with write batches instead of one write per record. It is meant
+// for catching up with the chain, where transactions arrive faster than they can be committed
+// one at a time.
+//
+// Every transaction is checked before anything is written. Auxiliary records are committed before
+// the transaction items, so that a failed commit is retried in full when the transactions are
+// added again.
+func (handler *TransactionStore) AddIncludedTransactions(trxs []*IncludedTransaction) error {
+	itemKeys := make([][]byte, len(trxs))
+	usageKeys := make([][]byte, 0, len(trxs))
+	for i, trx := range trxs {
+		if err := handler.checkIncludedTransaction(trx.Transaction, trx.Receipt); err != nil {
+			return err
+		}
+		itemKeys[i] = trx.Transaction.Id
+		if trx.Receipt != nil {
+			usageKeys = append(usageKeys, usageBucketKey(trx.Receipt, trx.Topology.Height))
+		}
+	}
+
+	defer handler.itemLocks.lockAll(itemKeys)()
+	defer handler.usageLocks.lockAll(usageKeys)()
+
+	overlay := &overlayBackend{backend: handler.backend, pending: make(map[string][]byte)}
+	staged := *handler
+	staged.backend = overlay
+	for _, trx := range trxs {
+		if err := staged.addIncludedTransaction(trx.Transaction, trx.Receipt, trx.Topology); err != nil {
+			return err
+		}
+	}
+
+	if err := handler.commitStaged(overlay, true); err != nil {
+		return err
+	}
+	if err := handler.commitStaged(overlay, false); err != nil {
+		return err
+	}
+
+	// Including a transaction again only adds a containing block record, so every item is invalidated
+	if handler.cache != nil {
+		for _, key := range itemKeys {
+			handler.cache.remove(key)
+		}
+	}
+
+	return nil
+}
+
+// commitStaged commits either the staged auxiliary records or the staged transaction items
+func (handler *TransactionStore) commitStaged(overlay *overlayBackend, auxiliary bool) error {
+	batch := handler.backend.NewWriteBatch()
+	for _, key := range overlay.keys {
+		if isAuxiliaryKey(key) != auxiliary {
+			continue
+		}
+		if err := batch.Put(key, overlay.pending[string(key)]); err != nil {
+			batch.Cancel()
+			return fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+	}
+
+	if err := batch.Flush(); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package trxstore
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+func dumpBackend(t *testing.T, b TransactionStoreBackend) map[string][]byte {
+	records := make(map[string][]byte)
+	if err := b.Iterate(nil, func(key []byte, value []byte) error {
+		records[string(key)] = value
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestAddIncludedTransactions(t *testing.T) {
+	key, err := util.GenerateKoinosKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payer := key.AddressBytes()
+
+	// Transactions signed by the same key, paid from the same account, and one of them included twice
+	trxs := make([]*IncludedTransaction, 0)
+	for i := uint64(1); i <= 5; i++ {
+		nonce, err := util.UInt64ToNonceBytes(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := makeSignedTransaction(t, &protocol.TransactionHeader{Payer: payer, RcLimit: i, Nonce: nonce}, key)
+		tx.Operations = []*protocol.Operation{{Op: &protocol.Operation_CallContract{CallContract: &protocol.CallContractOperation{ContractId: []byte{1}, EntryPoint: 2}}}}
+		receipt := &protocol.TransactionReceipt{
+			Id:     tx.Id,
+			Payer:  payer,
+			RcUsed: i,
+			Events: []*protocol.EventData{{Sequence: 0, Source: []byte{1}, Name: "transfer"}},
+		}
+		trxs = append(trxs, &IncludedTransaction{Transaction: tx, Receipt: receipt, Topology: &koinos.BlockTopology{Id: []byte{byte(i)}, Height: i}})
+	}
+	trxs = append(trxs, &IncludedTransaction{Transaction: trxs[0].Transaction, Receipt: trxs[0].Receipt, Topology: &koinos.BlockTopology{Id: []byte{9}, Height: 9}})
+
+	// Included again in the same batch as its first inclusion
+	trxs = append(trxs, &IncludedTransaction{Transaction: trxs[4].Transaction, Receipt: trxs[4].Receipt, Topology: &koinos.BlockTopology{Id: []byte{10}, Height: 10}})
+
+	for bType := range backendTypes {
+		expected := NewBackend(bType)
+		// The batches are staged with the settings of the store they are added to
+		expectedStore := NewTransactionStore(expected, WithCompression(CompressionSnappy))
+		for _, trx := range trxs {
+			if err := expectedStore.AddIncludedTransactionWithReceipt(trx.Transaction, trx.Receipt, trx.Topology); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		b := NewBackend(bType)
+		store := NewTransactionStore(b, WithCacheBytes(1<<20), WithCompression(CompressionSnappy))
+
+		// A cached item is invalidated by the batch
+		if err := store.AddIncludedTransactionWithReceipt(trxs[0].Transaction, trxs[0].Receipt, trxs[0].Topology); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.GetTransactionsByID([][]byte{trxs[0].Transaction.Id}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.AddIncludedTransactions(trxs[1:3]); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AddIncludedTransactions(trxs[3:]); err != nil {
+			t.Fatal(err)
+		}
+
+		// The batches store exactly the records that adding one transaction at a time does
+		expectedRecords := dumpBackend(t, expected)
+		records := dumpBackend(t, b)
+		if len(records) != len(expectedRecords) {
+			t.Fatalf("Expected %v records, got %v", len(expectedRecords), len(records))
+		}
+		for k, v := range expectedRecords {
+			if !bytes.Equal(records[k], v) {
+				t.Fatalf("Unexpected value of %x", k)
+			}
+		}
+
+		items, err := store.GetTransactionsByID([][]byte{trxs[0].Transaction.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 1 || len(items[0].ContainingBlocks) != 2 {
+			t.Fatal("Expected the cached item to be invalidated")
+		}
+
+		CloseBackend(expected)
+		CloseBackend(b)
+	}
+
+	// Nothing is written if a transaction is rejected
+	b := NewMapBackend()
+	store := NewTransactionStore(b)
+	mismatched := &IncludedTransaction{Transaction: &protocol.Transaction{Id: []byte{2}}, Receipt: &protocol.TransactionReceipt{Id: []byte{3}}, Topology: &koinos.BlockTopology{Id: []byte{1}}}
+	if err := store.AddIncludedTransactions([]*IncludedTransaction{trxs[0], mismatched}); !errors.Is(err, ErrReceiptMismatch) {
+		t.Fatal("Expected ErrReceiptMismatch, got ", err)
+	}
+	if records := dumpBackend(t, b); len(records) != 0 {
+		t.Fatalf("Expected no records, got %v", len(records))
+	}
+
+	store = NewTransactionStore(&ErrorBackend{})
+	if err := store.AddIncludedTransactions(trxs); !errors.Is(err, ErrBackend) {
+		t.Fatal("Expected ErrBackend, got ", err)
+	}
+}
@@ -0,0 +1,121 @@
+package trxstore
+
+import (
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+)
+
+// Transaction records only hold the transaction, which never changes once it
+// is stored. The blocks containing it are kept as separate records so that
+// including a transaction in another block does not rewrite it.
+//
+// Databases written before this layout kept the containing blocks in the
+// transaction record. They are still read, and moved out of the record when
+// the transaction is included again or by the migration to schema version 2.
+
+// getContainingBlocks returns the containing blocks stored separately from a transaction record
+func (handler *TransactionStore) getContainingBlocks(trxID []byte) ([][]byte, error) {
+	blocks := make([][]byte, 0, 1)
+	err := handler.backend.Iterate(containingBlockPrefix(trxID), func(key []byte, value []byte) error {
+		blocks = append(blocks, value)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return blocks, nil
+}
+
+// assembleItem adds the separately stored containing blocks to a transaction record read from the backend
+func (handler *TransactionStore) assembleItem(item *transaction_store.TransactionItem) error {
+	blocks, err := handler.getContainingBlocks(item.GetTransaction().GetId())
+	if err != nil {
+		return err
+	}
+
+	mergeContainingBlocks(item, blocks)
+	return nil
+}
+
+// mergeContainingBlocks adds containing blocks read separately from a transaction record to the record
+func mergeContainingBlocks(item *transaction_store.TransactionItem, blocks [][]byte) {
+	// A record that was being migrated may hold some of the same blocks
+	seen := make(map[string]struct{}, len(item.ContainingBlocks))
+	for _, blockID := range item.ContainingBlocks {
+		seen[string(blockID)] = struct{}{}
+	}
+	for _, blockID := range blocks {
+		if _, ok := seen[string(blockID)]; !ok {
+			item.ContainingBlocks = append(item.ContainingBlocks, blockID)
+		}
+	}
+}
+
+// addContainingBlocks stores the containing blocks of a transaction that are not already
+// stored, including any still held by its record. The record is rewritten without them,
+// after they have been stored, so the lock of the item must be held.
+func (handler *TransactionStore) addContainingBlocks(record *transaction_store.TransactionItem, blockIDs [][]byte) error {
+	trxID := record.Transaction.Id
+
+	stored, err := handler.getContainingBlocks(trxID)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(stored))
+	for _, blockID := range stored {
+		seen[string(blockID)] = struct{}{}
+	}
+
+	sequence := uint32(len(stored))
+	added := false
+	for _, blockID := range append(append([][]byte{}, record.ContainingBlocks...), blockIDs...) {
+		if _, ok := seen[string(blockID)]; ok {
+			continue
+		}
+		if err := handler.backend.Put(containingBlockKey(trxID, sequence), blockID); err != nil {
+			return fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+		seen[string(blockID)] = struct{}{}
+		sequence++
+		added = true
+	}
+
+	if len(record.ContainingBlocks) > 0 {
+		return handler.putItem(&transaction_store.TransactionItem{Transaction: record.Transaction})
+	}
+
+	if added && handler.cache != nil {
+		handler.cache.remove(trxID)
+	}
+
+	return nil
+}
+
+// migrateContainingBlocks moves the containing blocks held by a transaction record
+// written before they were stored separately into their own records
+func (handler *TransactionStore) migrateContainingBlocks(key []byte, value []byte) error {
+	if isAuxiliaryKey(key) {
+		return nil
+	}
+
+	record := &transaction_store.TransactionItem{}
+	if err := unmarshalItem(value, record); err != nil {
+		return err
+	}
+	if len(record.ContainingBlocks) == 0 || record.Transaction == nil {
+		return nil
+	}
+
+	defer handler.itemLocks.lock(key).Unlock()
+
+	// The record may have changed since it was read
+	current, err := handler.getRecord(key)
+	if err != nil || current == nil {
+		return err
+	}
+
+	return handler.addContainingBlocks(current, nil)
+}
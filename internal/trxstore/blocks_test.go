@@ -0,0 +1,119 @@
+package trxstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"google.golang.org/protobuf/proto"
+)
+
+func checkContainingBlocks(t *testing.T, store *TransactionStore, trxID []byte, expected ...byte) {
+	items, err := store.GetTransactionsByID([][]byte{trxID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || len(items[0].ContainingBlocks) != len(expected) {
+		t.Fatalf("Expected %v containing blocks, got %v", len(expected), items)
+	}
+	for i, blockID := range expected {
+		if !bytes.Equal(items[0].ContainingBlocks[i], []byte{blockID}) {
+			t.Fatalf("Expected containing blocks %v, got %v", expected, items[0].ContainingBlocks)
+		}
+	}
+}
+
+func TestContainingBlocks(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+		trx := &protocol.Transaction{Id: []byte{1}, Operations: []*protocol.Operation{{Op: &protocol.Operation_UploadContract{UploadContract: &protocol.UploadContractOperation{Bytecode: make([]byte, 1024)}}}}}
+
+		for _, blockID := range []byte{3, 1, 2, 1} {
+			if err := store.AddIncludedTransaction(trx, &koinos.BlockTopology{Id: []byte{blockID}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		checkContainingBlocks(t, store, trx.Id, 3, 1, 2)
+
+		// The transaction record holds the transaction alone
+		recordBytes, err := b.Get(trx.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := &transaction_store.TransactionItem{}
+		if err := proto.Unmarshal(recordBytes, record); err != nil {
+			t.Fatal(err)
+		}
+		if len(record.ContainingBlocks) != 0 || !proto.Equal(record.Transaction, trx) {
+			t.Fatalf("Unexpected transaction record %v", record)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestMigrateContainingBlocks(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b, WithCacheBytes(1<<20))
+
+		// Records written before containing blocks were stored separately
+		for id := byte(1); id <= 3; id++ {
+			record := &transaction_store.TransactionItem{
+				Transaction:      &protocol.Transaction{Id: []byte{id}},
+				ContainingBlocks: [][]byte{{10}, {10 + id}},
+			}
+			recordBytes, err := proto.Marshal(record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Put(record.Transaction.Id, recordBytes); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Legacy records are read as they were
+		checkContainingBlocks(t, store, []byte{1}, 10, 11)
+
+		// Including a legacy transaction again migrates it
+		if err := store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{1}}, &koinos.BlockTopology{Id: []byte{20}}); err != nil {
+			t.Fatal(err)
+		}
+		checkContainingBlocks(t, store, []byte{1}, 10, 11, 20)
+
+		version, err := store.GetSchemaVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != 1 {
+			t.Fatalf("Expected schema version 1, got %v", version)
+		}
+
+		if err := store.Migrate(func(version uint64, description string) {}); err != nil {
+			t.Fatal(err)
+		}
+		checkContainingBlocks(t, store, []byte{2}, 10, 12)
+		checkContainingBlocks(t, store, []byte{3}, 10, 13)
+
+		for id := byte(1); id <= 3; id++ {
+			record, err := store.getRecord([]byte{id})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(record.ContainingBlocks) != 0 {
+				t.Fatalf("Expected 0x%x to be migrated, got %v", id, record)
+			}
+		}
+
+		if _, err := store.Verify(func(problem *VerificationProblem) {
+			t.Error("Unexpected problem: ", problem)
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		CloseBackend(b)
+	}
+}
@@ -0,0 +1,128 @@
+package trxstore
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"google.golang.org/protobuf/proto"
+)
+
+// CacheStats are the statistics of the transaction item cache
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+
+	// Entries is the number of items currently cached
+	Entries uint64
+
+	// Bytes is the encoded size of the items currently cached
+	Bytes uint64
+}
+
+type cacheEntry struct {
+	id   string
+	item *transaction_store.TransactionItem
+	size int
+}
+
+// itemCache is an LRU cache of decoded transaction items, bounded by the total
+// encoded size of the items.
+//
+// Readers do not lock items, so a reader may decode an item just before it is
+// replaced. Each key stripe has a generation that is advanced when one of its
+// items is invalidated, and an item is only cached if the generation has not
+// changed since the reader missed.
+type itemCache struct {
+	mutex       sync.Mutex
+	maxBytes    int
+	bytes       int
+	entries     map[string]*list.Element
+	lru         *list.List
+	generations [lockStripes]uint64
+	stats       CacheStats
+}
+
+func newItemCache(maxBytes int) *itemCache {
+	return &itemCache{
+		maxBytes: maxBytes,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// get returns the cached item with the given ID, counting the lookup. On a miss
+// it returns nil and the generation to add the item read from the backend with.
+func (c *itemCache) get(id []byte) (*transaction_store.TransactionItem, uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[string(id)]
+	if !ok {
+		c.stats.Misses++
+		return nil, c.generations[stripe(id)]
+	}
+
+	c.stats.Hits++
+	c.lru.MoveToFront(element)
+	return element.Value.(*cacheEntry).item, 0
+}
+
+// add caches an item, evicting the least recently used items until it fits. The
+// item is not cached if it is larger than the whole cache or if it may have been
+// invalidated since it was read.
+func (c *itemCache) add(id []byte, item *transaction_store.TransactionItem, generation uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.generations[stripe(id)] != generation {
+		return
+	}
+
+	size := len(id) + proto.Size(item)
+	if size > c.maxBytes {
+		return
+	}
+
+	if element, ok := c.entries[string(id)]; ok {
+		c.removeElement(element)
+	}
+
+	for c.bytes+size > c.maxBytes {
+		c.removeElement(c.lru.Back())
+		c.stats.Evictions++
+	}
+
+	c.entries[string(id)] = c.lru.PushFront(&cacheEntry{id: string(id), item: item, size: size})
+	c.bytes += size
+}
+
+func (c *itemCache) removeElement(element *list.Element) {
+	entry := c.lru.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.id)
+	c.bytes -= entry.size
+}
+
+// remove invalidates the cached item with the given ID. It must be called after
+// the item has been replaced in the backend.
+func (c *itemCache) remove(id []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.generations[stripe(id)]++
+
+	if element, ok := c.entries[string(id)]; ok {
+		c.removeElement(element)
+	}
+}
+
+func (c *itemCache) statistics() CacheStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stats := c.stats
+	stats.Entries = uint64(c.lru.Len())
+	stats.Bytes = uint64(c.bytes)
+	return stats
+}
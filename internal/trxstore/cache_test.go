@@ -0,0 +1,89 @@
+package trxstore
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+)
+
+func TestItemCache(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		// Each item is 9 bytes encoded with its ID, so two of them fit
+		store := NewTransactionStore(b, WithCacheBytes(24))
+
+		for id := byte(1); id <= 3; id++ {
+			if err := store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{id}}, &koinos.BlockTopology{Id: []byte{id}, Height: uint64(id)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		get := func(id byte) [][]byte {
+			items, err := store.GetTransactionsByID([][]byte{{id}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != 1 {
+				t.Fatalf("Expected transaction %v", id)
+			}
+			return items[0].ContainingBlocks
+		}
+		expectStats := func(expected CacheStats) {
+			if stats := store.CacheStats(); stats != expected {
+				t.Fatalf("Expected cache stats %+v, got %+v", expected, stats)
+			}
+		}
+
+		get(1)
+		get(2)
+		get(1)
+		expectStats(CacheStats{Hits: 1, Misses: 2, Entries: 2, Bytes: 18})
+
+		// The least recently used item is evicted
+		get(3)
+		get(1)
+		expectStats(CacheStats{Hits: 2, Misses: 3, Evictions: 1, Entries: 2, Bytes: 18})
+		get(2)
+		expectStats(CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2, Bytes: 18})
+
+		// Including a cached transaction in another block invalidates it
+		if err := store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{2}}, &koinos.BlockTopology{Id: []byte{4}, Height: 4}); err != nil {
+			t.Fatal(err)
+		}
+		if blocks := get(2); len(blocks) != 2 || !bytes.Equal(blocks[1], []byte{4}) {
+			t.Fatalf("Expected the updated item, got %v", blocks)
+		}
+		expectStats(CacheStats{Hits: 2, Misses: 5, Evictions: 2, Entries: 2, Bytes: 21})
+
+		// Missing transactions are not cached
+		if items, err := store.GetTransactionsByID([][]byte{{9}}); err != nil || len(items) != 0 {
+			t.Fatal("Expected no transaction, got ", items, err)
+		}
+		expectStats(CacheStats{Hits: 2, Misses: 6, Evictions: 2, Entries: 2, Bytes: 21})
+
+		// Items larger than the whole cache are not cached
+		blocks := make([]*koinos.BlockTopology, 0, 8)
+		for id := byte(10); id < 18; id++ {
+			blocks = append(blocks, &koinos.BlockTopology{Id: []byte{id}, Height: uint64(id)})
+		}
+		for _, block := range blocks {
+			if err := store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{5}}, block); err != nil {
+				t.Fatal(err)
+			}
+		}
+		get(5)
+		expectStats(CacheStats{Hits: 2, Misses: 7, Evictions: 2, Entries: 2, Bytes: 21})
+
+		CloseBackend(b)
+	}
+
+	store := NewTransactionStore(NewMapBackend())
+	if _, err := store.GetTransactionsByID([][]byte{{1}}); err != nil {
+		t.Fatal(err)
+	}
+	if stats := store.CacheStats(); stats != (CacheStats{}) {
+		t.Fatal("Expected no cache stats when disabled, got ", stats)
+	}
+}
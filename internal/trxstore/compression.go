@@ -0,0 +1,130 @@
+package trxstore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"google.golang.org/protobuf/proto"
+)
+
+// Compressed transaction records begin with a header byte naming their
+// compression. A serialized protobuf message never begins with a byte below
+// 0x08, which would be field number 0, so records without a header are the
+// uncompressed message and both kinds of record can coexist in one database.
+const maxCompressionHeader = 0x07
+
+// Compression is the compression applied to stored transaction records
+type Compression byte
+
+const (
+	// CompressionNone stores transaction records uncompressed
+	CompressionNone Compression = iota
+
+	// CompressionSnappy compresses transaction records with snappy
+	CompressionSnappy
+
+	// CompressionZstd compresses transaction records with zstd
+	CompressionZstd
+)
+
+// ErrUnknownCompression occurs when a compression is not supported
+var ErrUnknownCompression = errors.New("unknown compression")
+
+var compressionNames = map[Compression]string{
+	CompressionNone:   "none",
+	CompressionSnappy: "snappy",
+	CompressionZstd:   "zstd",
+}
+
+func (c Compression) String() string {
+	if name, ok := compressionNames[c]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("unknown(%d)", byte(c))
+}
+
+// ParseCompression returns the compression with the given name
+func ParseCompression(name string) (Compression, error) {
+	for c, n := range compressionNames {
+		if strings.EqualFold(name, n) {
+			return c, nil
+		}
+	}
+
+	return CompressionNone, fmt.Errorf("%w '%s'", ErrUnknownCompression, name)
+}
+
+// The zstd encoder and decoder are safe for concurrent use and shared by every store
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+func initZstd() {
+	zstdOnce.Do(func() {
+		zstdEncoder, _ = zstd.NewWriter(nil)
+		zstdDecoder, _ = zstd.NewReader(nil)
+	})
+}
+
+// marshalItem serializes a transaction record, compressing it with the store's
+// compression if that makes it smaller
+func (handler *TransactionStore) marshalItem(item *transaction_store.TransactionItem) ([]byte, error) {
+	itemBytes, err := proto.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrSerialization, err)
+	}
+
+	var compressed []byte
+	switch handler.compression {
+	case CompressionNone:
+		return itemBytes, nil
+	case CompressionSnappy:
+		compressed = make([]byte, 1+snappy.MaxEncodedLen(len(itemBytes)))
+		compressed = compressed[:1+len(snappy.Encode(compressed[1:], itemBytes))]
+	case CompressionZstd:
+		initZstd()
+		compressed = zstdEncoder.EncodeAll(itemBytes, make([]byte, 1, 1+len(itemBytes)))
+	default:
+		return nil, fmt.Errorf("%w, %v", ErrSerialization, handler.compression)
+	}
+
+	if len(compressed) >= len(itemBytes) {
+		return itemBytes, nil
+	}
+
+	compressed[0] = byte(handler.compression)
+	return compressed, nil
+}
+
+// unmarshalItem deserializes a transaction record, which may be compressed with any compression
+func unmarshalItem(value []byte, item *transaction_store.TransactionItem) error {
+	if len(value) > 0 && value[0] <= maxCompressionHeader {
+		var err error
+		switch Compression(value[0]) {
+		case CompressionSnappy:
+			value, err = snappy.Decode(nil, value[1:])
+		case CompressionZstd:
+			initZstd()
+			value, err = zstdDecoder.DecodeAll(value[1:], nil)
+		default:
+			err = fmt.Errorf("%w %v", ErrUnknownCompression, Compression(value[0]))
+		}
+		if err != nil {
+			return fmt.Errorf("%w, %v", ErrDeserialization, err)
+		}
+	}
+
+	if err := proto.Unmarshal(value, item); err != nil {
+		return fmt.Errorf("%w, %v", ErrDeserialization, err)
+	}
+
+	return nil
+}
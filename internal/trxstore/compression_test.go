@@ -0,0 +1,108 @@
+package trxstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseCompression(t *testing.T) {
+	for _, c := range []Compression{CompressionNone, CompressionSnappy, CompressionZstd} {
+		parsed, err := ParseCompression(c.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != c {
+			t.Fatalf("Expected %v, got %v", c, parsed)
+		}
+	}
+
+	if _, err := ParseCompression("lz4"); !errors.Is(err, ErrUnknownCompression) {
+		t.Fatalf("Expected %v, got %v", ErrUnknownCompression, err)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	for bType := range backendTypes {
+		for _, c := range []Compression{CompressionNone, CompressionSnappy, CompressionZstd} {
+			b := NewBackend(bType)
+			store := NewTransactionStore(b, WithCompression(c))
+
+			upload := &protocol.Transaction{Id: []byte{1}, Operations: []*protocol.Operation{{Op: &protocol.Operation_UploadContract{UploadContract: &protocol.UploadContractOperation{Bytecode: make([]byte, 4096)}}}}}
+			small := &protocol.Transaction{Id: []byte{2}}
+			for _, trx := range []*protocol.Transaction{upload, small} {
+				if err := store.AddIncludedTransaction(trx, &koinos.BlockTopology{Id: []byte{1}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Transactions added in batches are compressed as well
+			batched := proto.Clone(upload).(*protocol.Transaction)
+			batched.Id = []byte{3}
+			if err := store.AddIncludedTransactions([]*IncludedTransaction{{Transaction: batched, Topology: &koinos.BlockTopology{Id: []byte{2}, Height: 2}}}); err != nil {
+				t.Fatal(err)
+			}
+
+			uploadBytes, err := proto.Marshal(upload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, trxID := range [][]byte{upload.Id, batched.Id} {
+				value, err := b.Get(trxID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c == CompressionNone {
+					if value[0] <= maxCompressionHeader {
+						t.Fatalf("Expected an uncompressed record, got header 0x%x", value[0])
+					}
+				} else if Compression(value[0]) != c || len(value) >= len(uploadBytes) {
+					t.Fatalf("Expected a record compressed with %v, got %v bytes with header 0x%x", c, len(value), value[0])
+				}
+			}
+
+			// Records that do not get smaller are stored uncompressed
+			value, err := b.Get(small.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value[0] <= maxCompressionHeader {
+				t.Fatalf("Expected an uncompressed record, got header 0x%x", value[0])
+			}
+
+			// Records are read regardless of the compression of the store reading them
+			for _, reader := range []*TransactionStore{store, NewTransactionStore(b), NewTransactionStore(b, WithCompression(CompressionZstd))} {
+				items, err := reader.GetTransactionsByID([][]byte{upload.Id, small.Id})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(items) != 2 || !proto.Equal(items[0].Transaction, upload) || !proto.Equal(items[1].Transaction, small) {
+					t.Fatalf("Unexpected items %v", items)
+				}
+			}
+
+			CloseBackend(b)
+		}
+	}
+}
+
+func TestUnknownCompression(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		if err := b.Put([]byte{1}, []byte{maxCompressionHeader, 1, 2, 3}); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := store.GetTransactionsByID([][]byte{{1}}); !errors.Is(err, ErrDeserialization) {
+			t.Fatalf("Expected %v, got %v", ErrDeserialization, err)
+		}
+
+		CloseBackend(b)
+	}
+}
@@ -0,0 +1,151 @@
+package trxstore
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+)
+
+func TestConcurrentIngestion(t *testing.T) {
+	const writers = 8
+	const blocks = 50
+	payer := []byte{0, 1}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b, WithCacheBytes(1<<20))
+
+		// Every writer includes the same transactions in its own blocks, paying from the same account
+		var wg sync.WaitGroup
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < blocks; i++ {
+					tx := &protocol.Transaction{Id: []byte{1, byte(i)}, Header: &protocol.TransactionHeader{Payer: payer}}
+					receipt := &protocol.TransactionReceipt{Id: tx.Id, Payer: payer, RcUsed: 1}
+					topology := &koinos.BlockTopology{Id: []byte{byte(w), byte(i)}, Height: uint64(i)}
+					if err := store.AddIncludedTransactionWithReceipt(tx, receipt, topology); err != nil {
+						t.Error(err)
+						return
+					}
+					if _, err := store.GetTransactionsByID([][]byte{tx.Id}); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		for i := 0; i < blocks; i++ {
+			items, err := store.GetTransactionsByID([][]byte{{1, byte(i)}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != 1 || len(items[0].ContainingBlocks) != writers {
+				t.Fatalf("Expected transaction %v in %v blocks, got %v", i, writers, items)
+			}
+		}
+
+		// Only the first inclusion of each transaction is counted
+		usage, err := store.GetResourceUsage(payer, 0, UsageBucketSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(usage) != 1 || usage[0].Transactions != blocks || usage[0].RcUsed != blocks {
+			t.Fatalf("Unexpected usage %+v", usage)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+// BenchmarkReadDuringSync measures the latency of reads while blocks are
+// ingested concurrently, reporting the median and 99th percentile
+func BenchmarkReadDuringSync(b *testing.B) {
+	const stored = 10000
+	const transactionsPerBlock = 50
+
+	backend := NewBackend(BadgerBackendType)
+	defer CloseBackend(backend)
+	store := NewTransactionStore(backend)
+
+	makeTransaction := func(n uint64) *protocol.Transaction {
+		id := make([]byte, 8)
+		binary.BigEndian.PutUint64(id, n)
+		id[0] = 0x12
+		return &protocol.Transaction{
+			Id:         id,
+			Header:     &protocol.TransactionHeader{Payer: id[6:]},
+			Operations: []*protocol.Operation{{Op: &protocol.Operation_CallContract{CallContract: &protocol.CallContractOperation{ContractId: id[7:]}}}},
+		}
+	}
+	for n := uint64(0); n < stored; n++ {
+		if err := store.AddIncludedTransaction(makeTransaction(n), &koinos.BlockTopology{Id: []byte{1}, Height: n / transactionsPerBlock}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	// Sync ingests new blocks until the benchmark is done
+	done := make(chan struct{})
+	var ingested sync.WaitGroup
+	next := uint64(stored)
+	for j := 0; j < 4; j++ {
+		ingested.Add(1)
+		go func() {
+			defer ingested.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				n := atomic.AddUint64(&next, 1)
+				if err := store.AddIncludedTransaction(makeTransaction(n), &koinos.BlockTopology{Id: []byte{2}, Height: n / transactionsPerBlock}); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		}()
+	}
+
+	var mutex sync.Mutex
+	latencies := make([]time.Duration, 0, b.N)
+	var seed uint64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		n := atomic.AddUint64(&seed, 7919)
+		local := make([]time.Duration, 0)
+		for pb.Next() {
+			n = (n*6364136223846793005 + 1442695040888963407) % stored
+			start := time.Now()
+			if _, err := store.GetTransactionsByID([][]byte{makeTransaction(n).Id}); err != nil {
+				b.Error(err)
+				return
+			}
+			local = append(local, time.Since(start))
+		}
+		mutex.Lock()
+		latencies = append(latencies, local...)
+		mutex.Unlock()
+	})
+	b.StopTimer()
+
+	close(done)
+	ingested.Wait()
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		b.ReportMetric(float64(latencies[len(latencies)/2].Nanoseconds()), "p50-ns")
+		b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns")
+	}
+	b.ReportMetric(float64(atomic.LoadUint64(&next)-stored), "ingested")
+}
@@ -0,0 +1,305 @@
+package trxstore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+var (
+	// ErrInvalidEncryptionKey occurs when an encryption key is not an AES-128, AES-192 or AES-256 key
+	ErrInvalidEncryptionKey = errors.New("invalid encryption key")
+
+	// ErrDecryption occurs when a stored value cannot be decrypted with any known key
+	ErrDecryption = errors.New("error decrypting value")
+)
+
+// ReadEncryptionKey reads an encryption key from a file holding 16, 24 or 32
+// raw bytes, or their hex encoding
+func ReadEncryptionKey(path string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := contents
+	if decoded, err := hex.DecodeString(string(bytes.TrimSpace(contents))); err == nil {
+		key = decoded
+	}
+
+	if err := checkEncryptionKey(key); err != nil {
+		return nil, fmt.Errorf("%w in %s", err, path)
+	}
+
+	return key, nil
+}
+
+// checkEncryptionKey returns an error if key is not a valid AES key
+func checkEncryptionKey(key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+
+	return fmt.Errorf("%w, expected 16, 24 or 32 bytes but was %v", ErrInvalidEncryptionKey, len(key))
+}
+
+// Encrypted values begin with the ID of the key they were encrypted with,
+// followed by the nonce and the AES-GCM ciphertext. The record key is
+// authenticated with the value so that values cannot be moved between keys.
+const (
+	encryptionKeyIDSize = 4
+	encryptionNonceSize = 12
+)
+
+// encryptionKey is an AES-GCM key and the ID identifying the values it encrypted
+type encryptionKey struct {
+	id   uint32
+	aead cipher.AEAD
+}
+
+func newEncryptionKey(key []byte) (*encryptionKey, error) {
+	if err := checkEncryptionKey(key); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrInvalidEncryptionKey, err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrInvalidEncryptionKey, err)
+	}
+
+	hash := sha256.Sum256(key)
+	return &encryptionKey{id: binary.BigEndian.Uint32(hash[:encryptionKeyIDSize]), aead: aead}, nil
+}
+
+// EncryptedBackend encrypts the values stored in a backend that does not
+// encrypt them itself. Keys are stored in the clear so that they keep their
+// order.
+type EncryptedBackend struct {
+	backend TransactionStoreBackend
+
+	// current encrypts every value written
+	current *encryptionKey
+
+	// keys holds every key values may be decrypted with by ID, including current
+	keys map[uint32]*encryptionKey
+}
+
+// NewEncryptedBackend creates a backend encrypting values with key before storing
+// them in backend. Values encrypted with any of the previous keys can still be
+// read, until they are rewritten by Reencrypt.
+func NewEncryptedBackend(backend TransactionStoreBackend, key []byte, previousKeys ...[]byte) (*EncryptedBackend, error) {
+	current, err := newEncryptionKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := &EncryptedBackend{backend: backend, current: current, keys: map[uint32]*encryptionKey{current.id: current}}
+	for _, previous := range previousKeys {
+		k, err := newEncryptionKey(previous)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := encrypted.keys[k.id]; !ok {
+			encrypted.keys[k.id] = k
+		}
+	}
+
+	return encrypted, nil
+}
+
+func (backend *EncryptedBackend) encrypt(key []byte, value []byte) ([]byte, error) {
+	if value == nil {
+		return nil, errors.New("Cannot put a nil value")
+	}
+
+	sealed := make([]byte, encryptionKeyIDSize+encryptionNonceSize, encryptionKeyIDSize+encryptionNonceSize+len(value)+backend.current.aead.Overhead())
+	binary.BigEndian.PutUint32(sealed, backend.current.id)
+	nonce := sealed[encryptionKeyIDSize:]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return backend.current.aead.Seal(sealed, nonce, value, key), nil
+}
+
+func (backend *EncryptedBackend) decrypt(key []byte, value []byte) ([]byte, error) {
+	if len(value) == 0 {
+		return value, nil
+	}
+	if len(value) < encryptionKeyIDSize+encryptionNonceSize {
+		return nil, fmt.Errorf("%w 0x%x, value is too short", ErrDecryption, key)
+	}
+
+	k, ok := backend.keys[binary.BigEndian.Uint32(value)]
+	if !ok {
+		return nil, fmt.Errorf("%w 0x%x, unknown encryption key", ErrDecryption, key)
+	}
+
+	nonce := value[encryptionKeyIDSize : encryptionKeyIDSize+encryptionNonceSize]
+	plain, err := k.aead.Open(nil, nonce, value[encryptionKeyIDSize+encryptionNonceSize:], key)
+	if err != nil {
+		return nil, fmt.Errorf("%w 0x%x, %v", ErrDecryption, key, err)
+	}
+
+	// A value that was empty before it was encrypted is still found
+	if plain == nil {
+		plain = make([]byte, 0)
+	}
+
+	return plain, nil
+}
+
+// Put encrypts and stores a value
+func (backend *EncryptedBackend) Put(key []byte, value []byte) error {
+	sealed, err := backend.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+
+	return backend.backend.Put(key, sealed)
+}
+
+// Get reads and decrypts a value
+func (backend *EncryptedBackend) Get(key []byte) ([]byte, error) {
+	value, err := backend.backend.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.decrypt(key, value)
+}
+
+// GetMany reads and decrypts several values from the same snapshot
+func (backend *EncryptedBackend) GetMany(keys [][]byte) ([][]byte, error) {
+	values, err := backend.backend.GetMany(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range values {
+		if values[i], err = backend.decrypt(keys[i], values[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// GetManyWithPrefixes reads and decrypts several values and the values with the given key prefixes from the same snapshot
+func (backend *EncryptedBackend) GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error) {
+	values, err := backend.backend.GetManyWithPrefixes(keys, prefixes, func(prefix int, key []byte, value []byte) error {
+		plain, err := backend.decrypt(key, value)
+		if err != nil {
+			return err
+		}
+		return f(prefix, key, plain)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range values {
+		if values[i], err = backend.decrypt(keys[i], values[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// Delete deletes a value
+func (backend *EncryptedBackend) Delete(key []byte) error {
+	return backend.backend.Delete(key)
+}
+
+// Iterate decrypts the values visited by the wrapped backend's iterator
+func (backend *EncryptedBackend) Iterate(prefix []byte, f func(key []byte, value []byte) error) error {
+	return backend.IterateFrom(prefix, nil, false, f)
+}
+
+// IterateFrom decrypts the values visited by the wrapped backend's iterator beginning at the given key
+func (backend *EncryptedBackend) IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error {
+	return backend.backend.IterateFrom(prefix, start, reverse, func(key []byte, value []byte) error {
+		plain, err := backend.decrypt(key, value)
+		if err != nil {
+			return err
+		}
+		return f(key, plain)
+	})
+}
+
+// NewWriteBatch creates a batch encrypting its values before adding them to a batch of the wrapped backend
+func (backend *EncryptedBackend) NewWriteBatch() WriteBatch {
+	return &encryptedWriteBatch{backend: backend, batch: backend.backend.NewWriteBatch()}
+}
+
+type encryptedWriteBatch struct {
+	backend *EncryptedBackend
+	batch   WriteBatch
+}
+
+func (wb *encryptedWriteBatch) Put(key []byte, value []byte) error {
+	sealed, err := wb.backend.encrypt(key, value)
+	if err != nil {
+		return err
+	}
+
+	return wb.batch.Put(key, sealed)
+}
+
+func (wb *encryptedWriteBatch) Flush() error {
+	return wb.batch.Flush()
+}
+
+func (wb *encryptedWriteBatch) Cancel() {
+	wb.batch.Cancel()
+}
+
+// Reset resets the wrapped backend
+func (backend *EncryptedBackend) Reset() error {
+	return backend.backend.Reset()
+}
+
+// Reencrypt rewrites every value that is not encrypted with the current key, so that previous
+// keys are no longer needed. It must not run while values are being written. Returns the number
+// of values rewritten.
+func (backend *EncryptedBackend) Reencrypt() (uint64, error) {
+	count := uint64(0)
+	var callbackErr error
+
+	err := backend.backend.Iterate(nil, func(key []byte, value []byte) error {
+		if len(value) >= encryptionKeyIDSize && binary.BigEndian.Uint32(value) == backend.current.id {
+			return nil
+		}
+
+		var plain []byte
+		if plain, callbackErr = backend.decrypt(key, value); callbackErr != nil {
+			return callbackErr
+		}
+		if callbackErr = backend.Put(key, plain); callbackErr != nil {
+			return callbackErr
+		}
+
+		count++
+		return nil
+	})
+	if callbackErr != nil {
+		return count, callbackErr
+	}
+
+	return count, err
+}
@@ -0,0 +1,169 @@
+package trxstore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+func TestReadEncryptionKey(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), "trxstore-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key := bytes.Repeat([]byte{7}, 32)
+	files := map[string][]byte{
+		"raw": key,
+		"hex": []byte(hex.EncodeToString(key) + "\n"),
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, contents, 0600); err != nil {
+			t.Fatal(err)
+		}
+		read, err := ReadEncryptionKey(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(read, key) {
+			t.Fatalf("Expected %s key 0x%x, got 0x%x", name, key, read)
+		}
+	}
+
+	path := filepath.Join(dir, "short")
+	if err := os.WriteFile(path, []byte("too short"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadEncryptionKey(path); !errors.Is(err, ErrInvalidEncryptionKey) {
+		t.Fatalf("Expected %v, got %v", ErrInvalidEncryptionKey, err)
+	}
+}
+
+func TestEncryptedBackend(t *testing.T) {
+	m := NewMapBackend()
+	b, err := NewEncryptedBackend(m, testEncryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Put([]byte("a"), []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := m.Get([]byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored, []byte("secret")) {
+		t.Fatal("Expected the stored value to be encrypted")
+	}
+
+	// A value moved to another key is not authentic
+	if err := m.Put([]byte("b"), stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get([]byte("b")); !errors.Is(err, ErrDecryption) {
+		t.Fatalf("Expected %v, got %v", ErrDecryption, err)
+	}
+	if err := m.Delete([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewEncryptedBackend(m, []byte("short")); !errors.Is(err, ErrInvalidEncryptionKey) {
+		t.Fatalf("Expected %v, got %v", ErrInvalidEncryptionKey, err)
+	}
+
+	newKey := bytes.Repeat([]byte{1}, 32)
+	other, err := NewEncryptedBackend(m, newKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Get([]byte("a")); !errors.Is(err, ErrDecryption) {
+		t.Fatalf("Expected %v, got %v", ErrDecryption, err)
+	}
+
+	// Rotate to the new key
+	rotated, err := NewEncryptedBackend(m, newKey, testEncryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rotated.Put([]byte("c"), []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := rotated.Reencrypt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected 1 value to be reencrypted, got %v", count)
+	}
+
+	for key, expected := range map[string]string{"a": "secret", "c": "new"} {
+		value, err := other.Get([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(value) != expected {
+			t.Fatalf("Expected %s, got %s", expected, value)
+		}
+	}
+}
+
+func TestBadgerEncryption(t *testing.T) {
+	dir, err := os.MkdirTemp(os.TempDir(), "trxstore-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	open := func(key []byte) (*BadgerBackend, error) {
+		opts, err := WithBadgerEncryption(badger.DefaultOptions(dir).WithLogger(nil), key, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return OpenBadgerBackend(opts)
+	}
+
+	b, err := open(testEncryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put([]byte("a"), []byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	b.Close()
+
+	newKey := bytes.Repeat([]byte{1}, 32)
+	if _, err := open(newKey); !errors.Is(err, badger.ErrEncryptionKeyMismatch) {
+		t.Fatalf("Expected %v, got %v", badger.ErrEncryptionKeyMismatch, err)
+	}
+
+	if err := RotateBadgerEncryptionKey(dir, newKey, testEncryptionKey); !errors.Is(err, badger.ErrEncryptionKeyMismatch) {
+		t.Fatalf("Expected %v, got %v", badger.ErrEncryptionKeyMismatch, err)
+	}
+	if err := RotateBadgerEncryptionKey(dir, testEncryptionKey, newKey); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err = open(newKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	value, err := b.Get([]byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "secret" {
+		t.Fatalf("Expected secret, got %s", value)
+	}
+}
@@ -0,0 +1,56 @@
+package trxstore
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+)
+
+// IndexedEvent is an event emitted by a stored transaction
+type IndexedEvent struct {
+	TransactionID []byte
+	Event         *protocol.EventData
+}
+
+// EventPage is a page of events returned by an event query
+type EventPage struct {
+	Events []*IndexedEvent
+
+	// Cursor continues the query with the next page, nil if there are no more events
+	Cursor []byte
+}
+
+// GetEvents returns a page of the events with the given source and name, ordered by the height
+// at which the emitting transaction was included and by their sequence within the transaction
+func (handler *TransactionStore) GetEvents(source []byte, name string, page *Pagination) (*EventPage, error) {
+	result := &EventPage{Events: make([]*IndexedEvent, 0)}
+	receipts := make(map[string]*protocol.TransactionReceipt)
+
+	cursor, err := handler.iterateIndex(indexPrefix(eventIndexNamespace, eventAttribute(source, name)), page, func(key []byte, value []byte) error {
+		receipt, ok := receipts[string(value)]
+		if !ok {
+			var err error
+			if receipt, err = handler.getReceipt(value); err != nil {
+				return err
+			}
+			receipts[string(value)] = receipt
+		}
+
+		sequence := binary.BigEndian.Uint32(key[len(key)-4:])
+		for _, event := range receipt.GetEvents() {
+			if event.Sequence == sequence {
+				result.Events = append(result.Events, &IndexedEvent{TransactionID: value, Event: event})
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%w, event %v of 0x%x is missing from its receipt", ErrDeserialization, sequence, value)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Cursor = cursor
+	return result, nil
+}
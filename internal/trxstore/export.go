@@ -0,0 +1,353 @@
+package trxstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore/storepb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// ExportFormat is the encoding of an export stream
+type ExportFormat int
+
+const (
+	// ProtobufFormat is a stream of varint length-delimited protobuf records
+	ProtobufFormat ExportFormat = iota
+
+	// JSONFormat is a stream of newline-delimited JSON records
+	JSONFormat
+)
+
+var (
+	// ErrUnknownFormat occurs when an export format is not recognized
+	ErrUnknownFormat = errors.New("unknown export format")
+
+	// ErrImportNotEmpty occurs when raw auxiliary records are imported into a store that already holds records
+	ErrImportNotEmpty = errors.New("auxiliary records can only be imported into an empty store")
+)
+
+// maxExportRecordSize is the largest export record that is read, so that a corrupt length cannot exhaust memory
+const maxExportRecordSize = 64 << 20
+
+// ParseExportFormat returns the export format with the given name
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch name {
+	case "protobuf", "pb":
+		return ProtobufFormat, nil
+	case "json", "jsonl", "ndjson":
+		return JSONFormat, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+}
+
+// exportRecord is a single entry of an export stream. Exactly one of item or
+// key/value is set. An item is exported with the heights of the first and the
+// latest blocks including it, so that it can be indexed again when it is
+// imported without the auxiliary records. Records of protobuf streams are
+// encoded as storepb.ExportRecord, so that a stream can be read by any
+// protobuf implementation.
+type exportRecord struct {
+	item       *transaction_store.TransactionItem
+	key        []byte
+	value      []byte
+	height     uint64
+	lastHeight uint64
+}
+
+type jsonExportRecord struct {
+	Item       json.RawMessage `json:"item,omitempty"`
+	Key        []byte          `json:"key,omitempty"`
+	Value      []byte          `json:"value,omitempty"`
+	Height     uint64          `json:"height,omitempty"`
+	LastHeight uint64          `json:"last_height,omitempty"`
+}
+
+// Export writes every transaction item to w in the given format. When
+// includeAuxiliary is set, auxiliary records such as receipts and indexes are
+// written as raw key-value pairs as well, before the items. Returns the number
+// of records written.
+func (handler *TransactionStore) Export(w io.Writer, format ExportFormat, includeAuxiliary bool) (uint64, error) {
+	bw := bufio.NewWriter(w)
+	count := uint64(0)
+
+	err := handler.backend.Iterate(nil, func(key []byte, value []byte) error {
+		record := &exportRecord{}
+		if isAuxiliaryKey(key) {
+			// Containing blocks are exported with their transaction, metadata and pending notifications
+			// describe this database alone
+			if !includeAuxiliary || !isExportedNamespace(key[len(auxiliaryPrefix)]) {
+				return nil
+			}
+			record.key = key
+			record.value = value
+		} else {
+			record.item = &transaction_store.TransactionItem{}
+			if err := unmarshalItem(value, record.item); err != nil {
+				return err
+			}
+			if err := handler.assembleItem(record.item); err != nil {
+				return err
+			}
+
+			indexRecord, err := handler.getIndexRecord(key)
+			if err != nil {
+				return err
+			}
+			if indexRecord != nil {
+				record.height = indexRecord.height
+				record.lastHeight = indexRecord.lastHeight
+			}
+		}
+
+		if err := writeExportRecord(bw, format, record); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err = bw.Flush(); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// isExportedNamespace returns true if the auxiliary records of a namespace are exported
+func isExportedNamespace(namespace byte) bool {
+	return namespace != containingBlockNamespace && namespace != metadataNamespace && namespace != outboxNamespace
+}
+
+// Import reads an export stream in the given format from r and stores every
+// record it contains. Items are added to those already stored, and indexed if
+// their auxiliary records are not imported with them. Raw auxiliary records
+// are only imported into an empty store, which they cannot conflict with.
+// Returns the number of records imported.
+func (handler *TransactionStore) Import(r io.Reader, format ExportFormat) (uint64, error) {
+	br := bufio.NewReader(r)
+	count := uint64(0)
+
+	empty, err := handler.isEmpty()
+	if err != nil {
+		return count, err
+	}
+
+	for {
+		record, err := readExportRecord(br, format)
+		if err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return count, err
+		}
+
+		if record.item != nil {
+			if record.item.Transaction == nil {
+				return count, fmt.Errorf("%w, transaction item without a transaction", ErrDeserialization)
+			}
+			if err := checkTransactionIDKey(record.item.Transaction.Id); err != nil {
+				return count, err
+			}
+			mutex := handler.itemLocks.lock(record.item.Transaction.Id)
+			err = handler.importItem(record)
+			mutex.Unlock()
+			if err != nil {
+				return count, err
+			}
+		} else {
+			if !isAuxiliaryKey(record.key) || !isExportedNamespace(record.key[len(auxiliaryPrefix)]) {
+				return count, fmt.Errorf("%w, raw record key 0x%x is not the key of an exported auxiliary record", ErrDeserialization, record.key)
+			}
+			if !empty {
+				return count, ErrImportNotEmpty
+			}
+			if err = handler.backend.Put(record.key, record.value); err != nil {
+				return count, fmt.Errorf("%w, %v", ErrBackend, err)
+			}
+		}
+
+		count++
+	}
+}
+
+// importItem stores an exported item, adding its containing blocks to those already stored. An item
+// without an index record, whose auxiliary records were not imported, is indexed at its exported heights.
+func (handler *TransactionStore) importItem(exported *exportRecord) error {
+	item := exported.item
+
+	record, err := handler.getRecord(item.Transaction.Id)
+	if err != nil {
+		return err
+	}
+	if record != nil {
+		if err := handler.retainUntil(item.Transaction.Id, exported.lastHeight); err != nil {
+			return err
+		}
+		return handler.addContainingBlocks(record, item.ContainingBlocks)
+	}
+
+	indexRecord, err := handler.getIndexRecord(item.Transaction.Id)
+	if err != nil {
+		return err
+	}
+	if indexRecord == nil {
+		receipt, err := handler.getReceipt(item.Transaction.Id)
+		if err != nil {
+			return err
+		}
+		if err := handler.addIndexes(item.Transaction, receipt, exported.height); err != nil {
+			return err
+		}
+		if err := handler.retainUntil(item.Transaction.Id, exported.lastHeight); err != nil {
+			return err
+		}
+	}
+
+	// The record is stored last so that an interrupted import is retried in full
+	record = &transaction_store.TransactionItem{Transaction: item.Transaction}
+	if err := handler.addContainingBlocks(record, item.ContainingBlocks); err != nil {
+		return err
+	}
+
+	return handler.putItem(record)
+}
+
+func writeExportRecord(w *bufio.Writer, format ExportFormat, record *exportRecord) error {
+	switch format {
+	case ProtobufFormat:
+		buf, err := proto.Marshal(&storepb.ExportRecord{
+			Item:       record.item,
+			Key:        record.key,
+			Value:      record.value,
+			Height:     record.height,
+			LastHeight: record.lastHeight,
+		})
+		if err != nil {
+			return fmt.Errorf("%w, %v", ErrSerialization, err)
+		}
+
+		if _, err := w.Write(protowire.AppendVarint(nil, uint64(len(buf)))); err != nil {
+			return err
+		}
+		_, err = w.Write(buf)
+		return err
+
+	case JSONFormat:
+		jsonRecord := &jsonExportRecord{Key: record.key, Value: record.value, Height: record.height, LastHeight: record.lastHeight}
+		if record.item != nil {
+			itemJSON, err := protojson.Marshal(record.item)
+			if err != nil {
+				return fmt.Errorf("%w, %v", ErrSerialization, err)
+			}
+			jsonRecord.Item = itemJSON
+		}
+
+		line, err := json.Marshal(jsonRecord)
+		if err != nil {
+			return fmt.Errorf("%w, %v", ErrSerialization, err)
+		}
+		if _, err = w.Write(line); err != nil {
+			return err
+		}
+		return w.WriteByte('\n')
+	}
+
+	return ErrUnknownFormat
+}
+
+func readExportRecord(r *bufio.Reader, format ExportFormat) (*exportRecord, error) {
+	record := &exportRecord{}
+
+	switch format {
+	case ProtobufFormat:
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if length > maxExportRecordSize {
+			return nil, fmt.Errorf("%w, record length %v exceeds %v bytes", ErrDeserialization, length, maxExportRecordSize)
+		}
+		buf := make([]byte, length)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+		}
+
+		message := &storepb.ExportRecord{}
+		if err = proto.Unmarshal(buf, message); err != nil {
+			return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+		}
+		record.item = message.Item
+		record.key = message.Key
+		record.value = message.Value
+		record.height = message.Height
+		record.lastHeight = message.LastHeight
+
+	case JSONFormat:
+		var line []byte
+		for len(line) == 0 {
+			var err error
+			line, err = readLine(r)
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 && err != nil {
+				return nil, err
+			}
+		}
+
+		jsonRecord := &jsonExportRecord{}
+		if err := json.Unmarshal(line, jsonRecord); err != nil {
+			return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+		}
+
+		if len(jsonRecord.Item) > 0 {
+			record.height = jsonRecord.Height
+			record.lastHeight = jsonRecord.LastHeight
+			record.item = &transaction_store.TransactionItem{}
+			if err := protojson.Unmarshal(jsonRecord.Item, record.item); err != nil {
+				return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+			}
+		} else {
+			record.key = jsonRecord.Key
+			record.value = jsonRecord.Value
+		}
+
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	if record.item == nil && len(record.key) == 0 {
+		return nil, fmt.Errorf("%w, empty export record", ErrDeserialization)
+	}
+	if record.item == nil && record.value == nil {
+		// Empty values are omitted from both formats
+		record.value = []byte{}
+	}
+
+	return record, nil
+}
+
+// readLine reads a line of at most maxExportRecordSize bytes, including the newline
+func readLine(r *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(line)+len(chunk) > maxExportRecordSize {
+			return nil, fmt.Errorf("%w, record exceeds %v bytes", ErrDeserialization, maxExportRecordSize)
+		}
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package trxstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+)
+
+func TestExportImport(t *testing.T) {
+	for _, format := range []ExportFormat{ProtobufFormat, JSONFormat} {
+		for bType := range backendTypes {
+			b := NewBackend(bType)
+			store := NewTransactionStore(b)
+
+			for i := byte(1); i <= 3; i++ {
+				trx := &protocol.Transaction{Id: []byte{i}, Signatures: [][]byte{{i, i}}}
+				for j := byte(1); j <= i; j++ {
+					if err := store.AddIncludedTransaction(trx, &koinos.BlockTopology{Id: []byte{j}, Height: uint64(j)}); err != nil {
+						t.Fatal("Error adding transaction: ", err)
+					}
+				}
+			}
+
+			auxKey := append(append([]byte{}, auxiliaryPrefix...), 'a')
+			if err := b.Put(auxKey, []byte("aux")); err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			count, err := store.Export(&buf, format, false)
+			if err != nil {
+				t.Fatal("Error exporting transactions: ", err)
+			}
+			if count != 3 {
+				t.Fatalf("Expected 3 exported records, got %v", count)
+			}
+			exported := append([]byte{}, buf.Bytes()...)
+
+			buf.Reset()
+			count, err = store.Export(&buf, format, true)
+			if err != nil {
+				t.Fatal("Error exporting transactions: ", err)
+			}
+			// Each transaction has an index record and a height index entry per inclusion in addition to the extra record
+			if count != 13 {
+				t.Fatalf("Expected 13 exported records, got %v", count)
+			}
+			exportedAux := buf.Bytes()
+
+			importBackend := NewBackend(bType)
+			importStore := NewTransactionStore(importBackend)
+
+			count, err = importStore.Import(bytes.NewReader(exported), format)
+			if err != nil {
+				t.Fatal("Error importing transactions: ", err)
+			}
+			if count != 3 {
+				t.Fatalf("Expected 3 imported records, got %v", count)
+			}
+
+			trxs, err := importStore.GetTransactionsByID([][]byte{{1}, {2}, {3}})
+			if err != nil {
+				t.Fatal("Error getting transaction: ", err)
+			}
+			if len(trxs) != 3 {
+				t.Fatal("Incorrect number of transactions returned")
+			}
+			for i, item := range trxs {
+				if !bytes.Equal(item.Transaction.Id, []byte{byte(i + 1)}) {
+					t.Fatal("Wrong transaction returned")
+				}
+				if !bytes.Equal(item.Transaction.Signatures[0], []byte{byte(i + 1), byte(i + 1)}) {
+					t.Fatal("Transaction not imported correctly")
+				}
+				if len(item.ContainingBlocks) != i+1 {
+					t.Fatal("Containing blocks not imported correctly")
+				}
+			}
+
+			// Items imported without their auxiliary records are indexed at their exported heights
+			record, err := importStore.getIndexRecord([]byte{3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if record == nil || record.height != 1 || record.retainedHeight() != 3 {
+				t.Fatal("Imported transaction not indexed: ", record)
+			}
+			value, err := importBackend.Get(heightIndexKey(3, []byte{3}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(value, []byte{3}) {
+				t.Fatal("Imported transaction missing from the height index")
+			}
+
+			value, err = importBackend.Get(auxKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(value) != 0 {
+				t.Fatal("Auxiliary record imported when it was not exported")
+			}
+
+			// Auxiliary records could overwrite those of the stored transactions
+			if _, err = importStore.Import(bytes.NewReader(exportedAux), format); !errors.Is(err, ErrImportNotEmpty) {
+				t.Fatal("Expected ErrImportNotEmpty importing auxiliary records into a non-empty store, got: ", err)
+			}
+
+			auxBackend := NewBackend(bType)
+			auxStore := NewTransactionStore(auxBackend)
+
+			count, err = auxStore.Import(bytes.NewReader(exportedAux), format)
+			if err != nil {
+				t.Fatal("Error importing transactions: ", err)
+			}
+			if count != 13 {
+				t.Fatalf("Expected 13 imported records, got %v", count)
+			}
+
+			value, err = auxBackend.Get(auxKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(value, []byte("aux")) {
+				t.Fatal("Auxiliary record not imported")
+			}
+
+			// A truncated stream must not be imported silently
+			_, err = importStore.Import(bytes.NewReader(exported[:len(exported)-3]), format)
+			if !errors.Is(err, ErrDeserialization) {
+				t.Fatal("Expected deserialization error importing truncated stream, got: ", err)
+			}
+
+			// An item whose ID collides with the auxiliary records must not be imported
+			var forged bytes.Buffer
+			w := bufio.NewWriter(&forged)
+			if err := writeExportRecord(w, format, &exportRecord{item: &transaction_store.TransactionItem{Transaction: &protocol.Transaction{Id: metadataKey(schemaVersionName)}}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = importStore.Import(&forged, format); !errors.Is(err, ErrInvalidTransactionID) {
+				t.Fatal("Expected ErrInvalidTransactionID importing a forged item, got: ", err)
+			}
+
+			// Raw records outside the exported auxiliary namespaces must not be imported
+			var raw bytes.Buffer
+			w = bufio.NewWriter(&raw)
+			if err := writeExportRecord(w, format, &exportRecord{key: []byte{1}, value: []byte{1}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err = NewTransactionStore(NewBackend(MapBackendType)).Import(&raw, format); !errors.Is(err, ErrDeserialization) {
+				t.Fatal("Expected deserialization error importing a raw transaction record, got: ", err)
+			}
+
+			CloseBackend(b)
+			CloseBackend(importBackend)
+			CloseBackend(auxBackend)
+		}
+	}
+
+	// Test error backend
+	{
+		store := NewTransactionStore(&ErrorBackend{})
+		var buf bytes.Buffer
+		if _, err := store.Export(&buf, ProtobufFormat, false); err == nil {
+			t.Fatal("Expected error exporting from error backend")
+		}
+	}
+
+	// Test bad record
+	{
+		store := NewTransactionStore(&BadBackend{})
+		var buf bytes.Buffer
+		if _, err := store.Export(&buf, ProtobufFormat, false); !errors.Is(err, ErrDeserialization) {
+			t.Fatal("Got unexpected error exporting bad record: ", err)
+		}
+	}
+
+	// Test oversized record
+	{
+		store := NewTransactionStore(NewBackend(MapBackendType))
+		length := make([]byte, binary.MaxVarintLen64)
+		n := binary.PutUvarint(length, maxExportRecordSize+1)
+		if _, err := store.Import(bytes.NewReader(length[:n]), ProtobufFormat); !errors.Is(err, ErrDeserialization) {
+			t.Fatal("Expected deserialization error importing oversized record, got: ", err)
+		}
+	}
+
+	if _, err := ParseExportFormat("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatal("Expected unknown format error")
+	}
+}
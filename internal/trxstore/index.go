@@ -0,0 +1,255 @@
+package trxstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore/storepb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultPageLimit is the number of transactions returned by an index query that does not set a limit
+	DefaultPageLimit = 100
+
+	// MaxPageLimit is the largest number of transactions returned by a single index query
+	MaxPageLimit = 1000
+)
+
+// ErrInvalidCursor occurs when a query cursor does not belong to the queried index
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// errPageFull stops index iteration once a page has been filled
+var errPageFull = errors.New("page full")
+
+// Pagination selects a page of an index query
+type Pagination struct {
+	// Cursor resumes a query where a previous page ended, nil starts from the beginning
+	Cursor []byte
+
+	// Limit is the maximum number of transactions to return
+	Limit uint32
+
+	// Descending returns the most recently included transactions first
+	Descending bool
+}
+
+// TransactionPage is a page of transactions returned by an index query
+type TransactionPage struct {
+	Transactions []*transaction_store.TransactionItem
+
+	// Cursor continues the query with the next page, nil if there are no more transactions
+	Cursor []byte
+}
+
+// indexRecord holds the attributes a transaction was indexed by that cannot
+// be derived from the transaction itself. It is encoded as storepb.IndexRecord.
+type indexRecord struct {
+	// height is the height at which the transaction was first included
+	height  uint64
+	signers [][]byte
+
+	// lastHeight is the height of the latest block including the transaction, if above height
+	lastHeight uint64
+}
+
+// retainedHeight returns the height the transaction is indexed at for pruning, that of the latest
+// block including it. A transaction is only pruned once every block including it is below the
+// pruned height, which includes the block that made it irreversible.
+func (record *indexRecord) retainedHeight() uint64 {
+	if record.lastHeight > record.height {
+		return record.lastHeight
+	}
+	return record.height
+}
+
+func (record *indexRecord) marshal() []byte {
+	message := &storepb.IndexRecord{
+		Height:     proto.Uint64(record.height),
+		Signers:    record.signers,
+		LastHeight: record.lastHeight,
+	}
+
+	// Marshalling a message of scalar fields cannot fail
+	buf, _ := proto.Marshal(message)
+	return buf
+}
+
+func unmarshalIndexRecord(buf []byte) (*indexRecord, error) {
+	message := &storepb.IndexRecord{}
+	if err := proto.Unmarshal(buf, message); err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+	}
+
+	return &indexRecord{
+		height:     message.GetHeight(),
+		signers:    message.Signers,
+		lastHeight: message.LastHeight,
+	}, nil
+}
+
+// indexEntries returns the keys of every index entry of the transaction.
+// Each entry's value is the ID of the transaction it refers to. The receipt
+// may be nil if the transaction was stored without one.
+func indexEntries(tx *protocol.Transaction, record *indexRecord, receipt *protocol.TransactionReceipt) [][]byte {
+	entries := make([][]byte, 0, 1+len(record.signers)+len(tx.Operations))
+	addEntry := func(key []byte) {
+		for _, entry := range entries {
+			if bytes.Equal(entry, key) {
+				return
+			}
+		}
+		entries = append(entries, key)
+	}
+
+	addEntry(heightIndexKey(record.retainedHeight(), tx.Id))
+
+	for _, signer := range record.signers {
+		addEntry(indexKey(signerIndexNamespace, signer, record.height, tx.Id))
+	}
+
+	for _, op := range tx.Operations {
+		if opType := operationType(op); opType != 0 {
+			addEntry(indexKey(operationTypeIndexNamespace, []byte{byte(opType)}, record.height, tx.Id))
+		}
+		if call := op.GetCallContract(); call != nil {
+			addEntry(indexKey(contractCallIndexNamespace, contractCallAttribute(call.ContractId, call.EntryPoint), record.height, tx.Id))
+		}
+	}
+
+	if account, nonce, ok := nonceAccount(tx); ok {
+		addEntry(nonceIndexKey(account, nonce, tx.Id))
+	}
+
+	for _, event := range receipt.GetEvents() {
+		addEntry(eventIndexKey(eventAttribute(event.Source, event.Name), record.height, tx.Id, event.Sequence))
+	}
+
+	return entries
+}
+
+// addIndexes indexes a transaction included for the first time at the given height
+func (handler *TransactionStore) addIndexes(tx *protocol.Transaction, receipt *protocol.TransactionReceipt, height uint64) error {
+	return handler.putIndexes(tx, receipt, &indexRecord{
+		height:  height,
+		signers: recoverSigners(tx),
+	})
+}
+
+// putIndexes stores the index entries of a transaction, then its index record
+func (handler *TransactionStore) putIndexes(tx *protocol.Transaction, receipt *protocol.TransactionReceipt, record *indexRecord) error {
+	for _, key := range indexEntries(tx, record, receipt) {
+		if err := handler.backend.Put(key, tx.Id); err != nil {
+			return fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+	}
+
+	if err := handler.backend.Put(indexRecordKey(tx.Id), record.marshal()); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// getIndexRecord returns the index record of a transaction, or nil if it has none
+func (handler *TransactionStore) getIndexRecord(trxID []byte) (*indexRecord, error) {
+	recordBytes, err := handler.backend.Get(indexRecordKey(trxID))
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+	if len(recordBytes) == 0 {
+		return nil, nil
+	}
+
+	return unmarshalIndexRecord(recordBytes)
+}
+
+// iterateIndex calls f with the entries with the given prefix that belong to
+// the requested page, returning the cursor of the next page
+func (handler *TransactionStore) iterateIndex(prefix []byte, page *Pagination, f func(key []byte, value []byte) error) ([]byte, error) {
+	if page == nil {
+		page = &Pagination{}
+	}
+
+	limit := int(page.Limit)
+	if limit == 0 {
+		limit = DefaultPageLimit
+	} else if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	if page.Cursor != nil && !bytes.HasPrefix(page.Cursor, prefix) {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor []byte
+	var callbackErr error
+	count := 0
+
+	err := handler.backend.IterateFrom(prefix, page.Cursor, page.Descending, func(key []byte, value []byte) error {
+		if count == limit {
+			cursor = key
+			return errPageFull
+		}
+		count++
+		callbackErr = f(key, value)
+		return callbackErr
+	})
+	if callbackErr != nil {
+		return nil, callbackErr
+	}
+	if err != nil && !errors.Is(err, errPageFull) {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return cursor, nil
+}
+
+// queryIndex returns a page of the transactions referred to by index entries with the given prefix
+func (handler *TransactionStore) queryIndex(prefix []byte, page *Pagination) (*TransactionPage, error) {
+	trxIDs := make([][]byte, 0)
+
+	cursor, err := handler.iterateIndex(prefix, page, func(key []byte, value []byte) error {
+		trxIDs = append(trxIDs, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TransactionPage{Cursor: cursor}
+	result.Transactions, err = handler.GetTransactionsByID(trxIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetTransactionsBySigner returns a page of the transactions signed by the given address, ordered by height
+func (handler *TransactionStore) GetTransactionsBySigner(signer []byte, page *Pagination) (*TransactionPage, error) {
+	return handler.queryIndex(indexPrefix(signerIndexNamespace, signer), page)
+}
+
+// GetTransactionsByContractCall returns a page of the transactions that call the given contract entry point, ordered by height
+func (handler *TransactionStore) GetTransactionsByContractCall(contractID []byte, entryPoint uint32, page *Pagination) (*TransactionPage, error) {
+	return handler.queryIndex(indexPrefix(contractCallIndexNamespace, contractCallAttribute(contractID, entryPoint)), page)
+}
+
+// GetTransactionsByOperationType returns a page of the transactions containing an operation of the given type, ordered by height
+func (handler *TransactionStore) GetTransactionsByOperationType(opType OperationType, page *Pagination) (*TransactionPage, error) {
+	return handler.queryIndex(indexPrefix(operationTypeIndexNamespace, []byte{byte(opType)}), page)
+}
+
+// GetTransactionSigners returns the addresses recovered from the signatures of a transaction
+func (handler *TransactionStore) GetTransactionSigners(trxID []byte) ([][]byte, error) {
+	record, err := handler.getIndexRecord(trxID)
+	if err != nil || record == nil {
+		return nil, err
+	}
+
+	return record.signers, nil
+}
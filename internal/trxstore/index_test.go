@@ -0,0 +1,537 @@
+package trxstore
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+func makeSignedTransaction(t *testing.T, header *protocol.TransactionHeader, keys ...*util.KoinosKey) *protocol.Transaction {
+	id, err := util.HashMessage(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trx := &protocol.Transaction{Id: id, Header: header}
+	for _, key := range keys {
+		if err = util.SignTransaction(key.PrivateBytes(), trx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return trx
+}
+
+func checkPage(t *testing.T, page *TransactionPage, expected ...*protocol.Transaction) {
+	if len(page.Transactions) != len(expected) {
+		t.Fatalf("Expected %v transactions, got %v", len(expected), len(page.Transactions))
+	}
+	for i, trx := range expected {
+		if !bytes.Equal(page.Transactions[i].Transaction.Id, trx.Id) {
+			t.Fatalf("Unexpected transaction at position %v", i)
+		}
+	}
+}
+
+func TestSignerIndex(t *testing.T) {
+	alice, err := util.GenerateKoinosKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := util.GenerateKoinosKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		trxs := []*protocol.Transaction{
+			makeSignedTransaction(t, &protocol.TransactionHeader{Nonce: []byte{1}}, alice),
+			makeSignedTransaction(t, &protocol.TransactionHeader{Nonce: []byte{2}}, alice, bob),
+			makeSignedTransaction(t, &protocol.TransactionHeader{Nonce: []byte{3}}, bob),
+			makeSignedTransaction(t, &protocol.TransactionHeader{Nonce: []byte{4}}, alice, alice),
+		}
+		// An unrecoverable signature is ignored
+		trxs[2].Signatures = append(trxs[2].Signatures, []byte{1, 2, 3})
+
+		for i, trx := range trxs {
+			topology := &koinos.BlockTopology{Id: []byte{byte(i)}, Height: uint64(10 - i)}
+			if err := store.AddIncludedTransaction(trx, topology); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+		}
+
+		// Including a transaction again does not index it again
+		if err := store.AddIncludedTransaction(trxs[0], &koinos.BlockTopology{Id: []byte{9}, Height: 1}); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+
+		signers, err := store.GetTransactionSigners(trxs[1].Id)
+		if err != nil {
+			t.Fatal("Error getting signers: ", err)
+		}
+		if len(signers) != 2 || !bytes.Equal(signers[0], alice.AddressBytes()) || !bytes.Equal(signers[1], bob.AddressBytes()) {
+			t.Fatal("Incorrect signers recovered")
+		}
+
+		signers, err = store.GetTransactionSigners(trxs[3].Id)
+		if err != nil {
+			t.Fatal("Error getting signers: ", err)
+		}
+		if len(signers) != 1 || !bytes.Equal(signers[0], alice.AddressBytes()) {
+			t.Fatal("Duplicate signatures should be recovered once")
+		}
+
+		signers, err = store.GetTransactionSigners([]byte{1})
+		if err != nil || signers != nil {
+			t.Fatal("Expected no signers for an unknown transaction")
+		}
+
+		// Transactions are ordered by height
+		page, err := store.GetTransactionsBySigner(alice.AddressBytes(), nil)
+		if err != nil {
+			t.Fatal("Error querying signer index: ", err)
+		}
+		checkPage(t, page, trxs[3], trxs[1], trxs[0])
+		if page.Cursor != nil {
+			t.Fatal("Expected the last page")
+		}
+
+		page, err = store.GetTransactionsBySigner(alice.AddressBytes(), &Pagination{Limit: 2, Descending: true})
+		if err != nil {
+			t.Fatal("Error querying signer index: ", err)
+		}
+		checkPage(t, page, trxs[0], trxs[1])
+		if page.Cursor == nil {
+			t.Fatal("Expected another page")
+		}
+
+		page, err = store.GetTransactionsBySigner(alice.AddressBytes(), &Pagination{Limit: 2, Descending: true, Cursor: page.Cursor})
+		if err != nil {
+			t.Fatal("Error querying signer index: ", err)
+		}
+		checkPage(t, page, trxs[3])
+		if page.Cursor != nil {
+			t.Fatal("Expected the last page")
+		}
+
+		page, err = store.GetTransactionsBySigner(bob.AddressBytes(), &Pagination{Limit: 1})
+		if err != nil {
+			t.Fatal("Error querying signer index: ", err)
+		}
+		checkPage(t, page, trxs[2])
+		bobCursor := page.Cursor
+
+		page, err = store.GetTransactionsBySigner(bob.AddressBytes(), &Pagination{Limit: 1, Cursor: bobCursor})
+		if err != nil {
+			t.Fatal("Error querying signer index: ", err)
+		}
+		checkPage(t, page, trxs[1])
+
+		_, err = store.GetTransactionsBySigner(alice.AddressBytes(), &Pagination{Cursor: bobCursor})
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Fatal("Expected invalid cursor error, got: ", err)
+		}
+
+		page, err = store.GetTransactionsBySigner([]byte{1}, nil)
+		if err != nil {
+			t.Fatal("Error querying signer index: ", err)
+		}
+		checkPage(t, page)
+
+		result, err := store.Verify(func(problem *VerificationProblem) {
+			t.Error("Unexpected problem: ", problem)
+		})
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+		// Index records and entries, and a height index entry and containing block record per transaction
+		if result.AuxiliaryRecords != 18 {
+			t.Fatalf("Expected 18 auxiliary records, got %v", result.AuxiliaryRecords)
+		}
+
+		// A dangling index entry is reported
+		if err = b.Put(indexKey(signerIndexNamespace, bob.AddressBytes(), 1, trxs[0].Id), trxs[0].Id); err != nil {
+			t.Fatal(err)
+		}
+		problems := 0
+		if _, err = store.Verify(func(*VerificationProblem) { problems++ }); err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+		if problems != 1 {
+			t.Fatalf("Expected 1 problem, got %v", problems)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestIterateFrom(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+
+		keys := [][]byte{{1}, {2, 0}, {2, 1}, {2, 255}, {2, 255, 255}, {3}}
+		for _, key := range keys {
+			if err := b.Put(key, key); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		collect := func(prefix []byte, start []byte, reverse bool) [][]byte {
+			visited := make([][]byte, 0)
+			err := b.IterateFrom(prefix, start, reverse, func(key []byte, value []byte) error {
+				visited = append(visited, key)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return visited
+		}
+
+		expect := func(visited [][]byte, expected ...[]byte) {
+			if len(visited) != len(expected) {
+				t.Fatalf("Expected %v keys, visited %v", len(expected), visited)
+			}
+			for i := range expected {
+				if !bytes.Equal(visited[i], expected[i]) {
+					t.Fatalf("Expected %v, visited %v", expected, visited)
+				}
+			}
+		}
+
+		expect(collect([]byte{2}, nil, false), keys[1], keys[2], keys[3], keys[4])
+		expect(collect([]byte{2}, nil, true), keys[4], keys[3], keys[2], keys[1])
+		expect(collect([]byte{2}, []byte{2, 1}, false), keys[2], keys[3], keys[4])
+		expect(collect([]byte{2}, []byte{2, 1}, true), keys[2], keys[1])
+		expect(collect([]byte{2}, []byte{2, 128}, true), keys[2], keys[1])
+		expect(collect([]byte{2, 255}, nil, true), keys[4], keys[3])
+		expect(collect(nil, nil, true), keys[5], keys[4], keys[3], keys[2], keys[1], keys[0])
+		expect(collect([]byte{4}, nil, true))
+
+		CloseBackend(b)
+	}
+}
+
+func TestContractCallIndex(t *testing.T) {
+	token := []byte{1, 2, 3}
+	transfer := uint32(0x27f576ca)
+	call := func(contractID []byte, entryPoint uint32) *protocol.Operation {
+		return &protocol.Operation{
+			Op: &protocol.Operation_CallContract{
+				CallContract: &protocol.CallContractOperation{ContractId: contractID, EntryPoint: entryPoint},
+			},
+		}
+	}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		trxs := []*protocol.Transaction{
+			{Id: []byte{1}, Operations: []*protocol.Operation{call(token, transfer)}},
+			{Id: []byte{2}, Operations: []*protocol.Operation{call(token, transfer+1)}},
+			{Id: []byte{3}, Operations: []*protocol.Operation{call(token, transfer), call(token, transfer)}},
+			{Id: []byte{4}, Operations: []*protocol.Operation{call([]byte{1, 2}, transfer)}},
+			{Id: []byte{5}, Operations: []*protocol.Operation{{Op: &protocol.Operation_UploadContract{UploadContract: &protocol.UploadContractOperation{ContractId: token}}}}},
+		}
+
+		for i, trx := range trxs {
+			topology := &koinos.BlockTopology{Id: []byte{byte(i)}, Height: uint64(i)}
+			if err := store.AddIncludedTransaction(trx, topology); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+		}
+
+		page, err := store.GetTransactionsByContractCall(token, transfer, nil)
+		if err != nil {
+			t.Fatal("Error querying contract call index: ", err)
+		}
+		checkPage(t, page, trxs[0], trxs[2])
+
+		page, err = store.GetTransactionsByContractCall(token, transfer, &Pagination{Limit: 1, Descending: true})
+		if err != nil {
+			t.Fatal("Error querying contract call index: ", err)
+		}
+		checkPage(t, page, trxs[2])
+
+		page, err = store.GetTransactionsByContractCall(token, transfer, &Pagination{Limit: 1, Descending: true, Cursor: page.Cursor})
+		if err != nil {
+			t.Fatal("Error querying contract call index: ", err)
+		}
+		checkPage(t, page, trxs[0])
+
+		page, err = store.GetTransactionsByContractCall(token, transfer+1, nil)
+		if err != nil {
+			t.Fatal("Error querying contract call index: ", err)
+		}
+		checkPage(t, page, trxs[1])
+
+		page, err = store.GetTransactionsByContractCall([]byte{1, 2}, transfer, nil)
+		if err != nil {
+			t.Fatal("Error querying contract call index: ", err)
+		}
+		checkPage(t, page, trxs[3])
+
+		_, err = store.Verify(func(problem *VerificationProblem) {
+			t.Error("Unexpected problem: ", problem)
+		})
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestOperationTypeIndex(t *testing.T) {
+	upload := &protocol.Operation{Op: &protocol.Operation_UploadContract{UploadContract: &protocol.UploadContractOperation{ContractId: []byte{1}}}}
+	call := &protocol.Operation{Op: &protocol.Operation_CallContract{CallContract: &protocol.CallContractOperation{ContractId: []byte{1}}}}
+	systemCall := &protocol.Operation{Op: &protocol.Operation_SetSystemCall{SetSystemCall: &protocol.SetSystemCallOperation{CallId: 1}}}
+	systemContract := &protocol.Operation{Op: &protocol.Operation_SetSystemContract{SetSystemContract: &protocol.SetSystemContractOperation{ContractId: []byte{1}, SystemContract: true}}}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		trxs := []*protocol.Transaction{
+			{Id: []byte{1}, Operations: []*protocol.Operation{upload, call}},
+			{Id: []byte{2}, Operations: []*protocol.Operation{systemCall, systemCall}},
+			{Id: []byte{3}, Operations: []*protocol.Operation{call}},
+			{Id: []byte{4}, Operations: []*protocol.Operation{systemContract, systemCall}},
+		}
+
+		for i, trx := range trxs {
+			topology := &koinos.BlockTopology{Id: []byte{byte(i)}, Height: uint64(i)}
+			if err := store.AddIncludedTransaction(trx, topology); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+		}
+
+		for opType, expected := range map[OperationType][]*protocol.Transaction{
+			UploadContractOperation:    {trxs[0]},
+			CallContractOperation:      {trxs[0], trxs[2]},
+			SetSystemCallOperation:     {trxs[1], trxs[3]},
+			SetSystemContractOperation: {trxs[3]},
+		} {
+			page, err := store.GetTransactionsByOperationType(opType, nil)
+			if err != nil {
+				t.Fatal("Error querying operation type index: ", err)
+			}
+			checkPage(t, page, expected...)
+		}
+
+		_, err := store.Verify(func(problem *VerificationProblem) {
+			t.Error("Unexpected problem: ", problem)
+		})
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+
+		CloseBackend(b)
+	}
+
+	for _, opType := range []OperationType{UploadContractOperation, CallContractOperation, SetSystemCallOperation, SetSystemContractOperation} {
+		parsed, err := ParseOperationType(opType.String())
+		if err != nil || parsed != opType {
+			t.Fatalf("Could not parse operation type %v", opType)
+		}
+	}
+	if _, err := ParseOperationType("transfer"); !errors.Is(err, ErrUnknownOperationType) {
+		t.Fatal("Expected unknown operation type error, got: ", err)
+	}
+}
+
+func TestEventIndex(t *testing.T) {
+	token := []byte{1, 2, 3}
+	event := func(sequence uint32, source []byte, name string) *protocol.EventData {
+		return &protocol.EventData{Sequence: sequence, Source: source, Name: name, Data: []byte{byte(sequence)}}
+	}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		trxs := []*protocol.Transaction{{Id: []byte{1}}, {Id: []byte{2}}, {Id: []byte{3}}}
+		receipts := []*protocol.TransactionReceipt{
+			{Id: trxs[0].Id, Events: []*protocol.EventData{event(0, token, "transfer"), event(1, token, "transfer")}},
+			{Id: trxs[1].Id, Events: []*protocol.EventData{event(0, token, "mint"), event(1, []byte{1, 2}, "transfer")}},
+			{Id: trxs[2].Id, Events: []*protocol.EventData{event(5, token, "transfer")}, StateDeltaEntries: []*protocol.StateDeltaEntry{{Key: []byte{1}}}},
+		}
+
+		for i, trx := range trxs {
+			topology := &koinos.BlockTopology{Id: []byte{byte(i)}, Height: uint64(i)}
+			if err := store.AddIncludedTransactionWithReceipt(trx, receipts[i], topology); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+		}
+
+		// A receipt is only indexed when a transaction is first included
+		if err := store.AddIncludedTransactionWithReceipt(trxs[0], receipts[0], &koinos.BlockTopology{Id: []byte{9}, Height: 9}); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+
+		err := store.AddIncludedTransactionWithReceipt(&protocol.Transaction{Id: []byte{4}}, receipts[0], &koinos.BlockTopology{Id: []byte{4}, Height: 4})
+		if !errors.Is(err, ErrReceiptMismatch) {
+			t.Fatal("Expected receipt mismatch error, got: ", err)
+		}
+
+		checkEvents := func(page *EventPage, expected ...[]byte) {
+			if len(page.Events) != len(expected) {
+				t.Fatalf("Expected %v events, got %v", len(expected), len(page.Events))
+			}
+			for i, e := range expected {
+				if !bytes.Equal(page.Events[i].TransactionID, e[:1]) || page.Events[i].Event.Sequence != uint32(e[1]) || !bytes.Equal(page.Events[i].Event.Data, e[1:]) {
+					t.Fatalf("Unexpected event at position %v", i)
+				}
+			}
+		}
+
+		page, err := store.GetEvents(token, "transfer", nil)
+		if err != nil {
+			t.Fatal("Error querying event index: ", err)
+		}
+		checkEvents(page, []byte{1, 0}, []byte{1, 1}, []byte{3, 5})
+
+		page, err = store.GetEvents(token, "transfer", &Pagination{Limit: 2, Descending: true})
+		if err != nil {
+			t.Fatal("Error querying event index: ", err)
+		}
+		checkEvents(page, []byte{3, 5}, []byte{1, 1})
+
+		page, err = store.GetEvents(token, "transfer", &Pagination{Limit: 2, Descending: true, Cursor: page.Cursor})
+		if err != nil {
+			t.Fatal("Error querying event index: ", err)
+		}
+		checkEvents(page, []byte{1, 0})
+		if page.Cursor != nil {
+			t.Fatal("Expected the last page")
+		}
+
+		page, err = store.GetEvents(token, "mint", nil)
+		if err != nil {
+			t.Fatal("Error querying event index: ", err)
+		}
+		checkEvents(page, []byte{2, 0})
+
+		page, err = store.GetEvents([]byte{1, 2}, "transfer", nil)
+		if err != nil {
+			t.Fatal("Error querying event index: ", err)
+		}
+		checkEvents(page, []byte{2, 1})
+
+		// State delta entries are not stored
+		receipt, err := store.getReceipt(trxs[2].Id)
+		if err != nil || receipt == nil {
+			t.Fatal("Error getting receipt: ", err)
+		}
+		if len(receipt.StateDeltaEntries) != 0 {
+			t.Fatal("Expected state delta entries to be dropped")
+		}
+
+		_, err = store.Verify(func(problem *VerificationProblem) {
+			t.Error("Unexpected problem: ", problem)
+		})
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestNonceIndex(t *testing.T) {
+	nonce := func(value uint64) []byte {
+		nonceBytes, err := util.UInt64ToNonceBytes(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nonceBytes
+	}
+
+	alice := []byte{0, 1}
+	bob := []byte{0, 2}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		trxs := []*protocol.Transaction{
+			{Id: []byte{1}, Header: &protocol.TransactionHeader{Payer: alice, Nonce: nonce(3)}},
+			{Id: []byte{2}, Header: &protocol.TransactionHeader{Payer: alice, Nonce: nonce(1)}},
+			{Id: []byte{3}, Header: &protocol.TransactionHeader{Payer: alice, Nonce: nonce(300)}},
+			// The payee's nonce is used when there is one
+			{Id: []byte{4}, Header: &protocol.TransactionHeader{Payer: bob, Payee: alice, Nonce: nonce(4)}},
+			// A transaction with the same nonce included in another fork
+			{Id: []byte{5}, Header: &protocol.TransactionHeader{Payer: alice, Nonce: nonce(1)}},
+			// Invalid nonces are not indexed
+			{Id: []byte{6}, Header: &protocol.TransactionHeader{Payer: alice, Nonce: []byte{1}}},
+		}
+
+		for i, trx := range trxs {
+			topology := &koinos.BlockTopology{Id: []byte{byte(i)}, Height: uint64(i)}
+			if err := store.AddIncludedTransaction(trx, topology); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+		}
+
+		items, err := store.GetTransactionsByNonce(alice, 1)
+		if err != nil {
+			t.Fatal("Error querying nonce index: ", err)
+		}
+		checkPage(t, &TransactionPage{Transactions: items}, trxs[1], trxs[4])
+
+		items, err = store.GetTransactionsByNonce(alice, 2)
+		if err != nil {
+			t.Fatal("Error querying nonce index: ", err)
+		}
+		checkPage(t, &TransactionPage{Transactions: items})
+
+		items, err = store.GetTransactionsByNonce(bob, 4)
+		if err != nil {
+			t.Fatal("Error querying nonce index: ", err)
+		}
+		checkPage(t, &TransactionPage{Transactions: items})
+
+		// Nonces are ordered by value
+		page, err := store.GetNonces(alice, &Pagination{Limit: 4})
+		if err != nil {
+			t.Fatal("Error querying nonce index: ", err)
+		}
+		expected := []NonceEntry{{1, trxs[1].Id}, {1, trxs[4].Id}, {3, trxs[0].Id}, {4, trxs[3].Id}}
+		if len(page.Nonces) != len(expected) {
+			t.Fatalf("Expected %v nonces, got %v", len(expected), len(page.Nonces))
+		}
+		for i := range expected {
+			if page.Nonces[i].Nonce != expected[i].Nonce || !bytes.Equal(page.Nonces[i].TransactionID, expected[i].TransactionID) {
+				t.Fatalf("Unexpected nonce at position %v", i)
+			}
+		}
+
+		page, err = store.GetNonces(alice, &Pagination{Limit: 4, Cursor: page.Cursor})
+		if err != nil {
+			t.Fatal("Error querying nonce index: ", err)
+		}
+		if len(page.Nonces) != 1 || page.Nonces[0].Nonce != 300 || page.Cursor != nil {
+			t.Fatal("Unexpected last page of nonces")
+		}
+
+		// The transaction IDs in this test are not hashes of their headers
+		_, err = store.Verify(func(problem *VerificationProblem) {
+			if isAuxiliaryKey(problem.Key) {
+				t.Error("Unexpected problem: ", problem)
+			}
+		})
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+
+		CloseBackend(b)
+	}
+}
@@ -0,0 +1,199 @@
+package trxstore
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Transaction records are stored under their raw transaction ID. Every other
+// record kept by the store (receipts, indexes, metadata) lives under
+// auxiliaryPrefix so that it can never collide with a transaction record.
+// Transaction IDs are multihashes and never begin with a zero byte, IDs that
+// do are rejected wherever transactions are added or read by ID.
+var auxiliaryPrefix = []byte{0x00}
+
+// Namespaces of auxiliary records, the byte following auxiliaryPrefix
+const (
+	// indexRecordNamespace holds, per transaction, the attributes it was indexed by
+	indexRecordNamespace byte = iota + 1
+
+	// signerIndexNamespace indexes transactions by the addresses that signed them
+	signerIndexNamespace
+
+	// contractCallIndexNamespace indexes transactions by the contract entry points they call
+	contractCallIndexNamespace
+
+	// operationTypeIndexNamespace indexes transactions by the types of their operations
+	operationTypeIndexNamespace
+
+	// receiptNamespace holds the receipts of transactions
+	receiptNamespace
+
+	// eventIndexNamespace indexes the events emitted by transactions by their source and name
+	eventIndexNamespace
+
+	// nonceIndexNamespace indexes transactions by the account and value of their nonce
+	nonceIndexNamespace
+
+	// usageNamespace holds the resources used by each account, aggregated in buckets of blocks
+	usageNamespace
+
+	// outboxNamespace holds notifications waiting to be delivered, ordered by their ID
+	outboxNamespace
+
+	// containingBlockNamespace holds the blocks containing each transaction, in the order they were added
+	containingBlockNamespace
+
+	// metadataNamespace holds records describing the database itself, such as its schema version
+	metadataNamespace
+
+	// heightIndexNamespace indexes every transaction by the height of the latest block including it
+	heightIndexNamespace
+
+	// blockTimeNamespace held the timestamp of each indexed block by height, before schema version 5
+	blockTimeNamespace
+
+	// blockTimeIndexNamespace holds the height of recorded blocks by their timestamp
+	blockTimeIndexNamespace
+)
+
+// isAuxiliaryKey returns true if the key does not belong to a transaction record
+func isAuxiliaryKey(key []byte) bool {
+	return bytes.HasPrefix(key, auxiliaryPrefix)
+}
+
+// prefixEnd returns the first key that sorts after every key with the given
+// prefix, or nil if there is no such key
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
+
+// auxiliaryKey returns the key of an auxiliary record in the given namespace
+func auxiliaryKey(namespace byte, parts ...[]byte) []byte {
+	key := append(append([]byte{}, auxiliaryPrefix...), namespace)
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+
+	return key
+}
+
+// indexRecordKey returns the key of a transaction's index record
+func indexRecordKey(trxID []byte) []byte {
+	return auxiliaryKey(indexRecordNamespace, trxID)
+}
+
+// receiptKey returns the key of a transaction's receipt
+func receiptKey(trxID []byte) []byte {
+	return auxiliaryKey(receiptNamespace, trxID)
+}
+
+// contractCallAttribute returns the index attribute of a call to a contract entry point
+func contractCallAttribute(contractID []byte, entryPoint uint32) []byte {
+	attribute := append([]byte{}, contractID...)
+	attribute = append(attribute, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(attribute[len(contractID):], entryPoint)
+	return attribute
+}
+
+// eventAttribute returns the index attribute of events with the given source and name
+func eventAttribute(source []byte, name string) []byte {
+	attribute := protowire.AppendVarint(nil, uint64(len(source)))
+	attribute = append(attribute, source...)
+	return append(attribute, name...)
+}
+
+// indexPrefix returns the prefix shared by every index entry with the given attribute
+//
+// The attribute is length prefixed so that no attribute's entries can share a
+// prefix with those of a longer attribute.
+func indexPrefix(namespace byte, attribute []byte) []byte {
+	return auxiliaryKey(namespace, protowire.AppendVarint(nil, uint64(len(attribute))), attribute)
+}
+
+// indexKey returns the key of an index entry. Entries with the same attribute
+// are ordered by the height at which the transaction was first included.
+func indexKey(namespace byte, attribute []byte, height uint64, trxID []byte) []byte {
+	key := append(indexPrefix(namespace, attribute), heightBytes(height)...)
+	return append(key, trxID...)
+}
+
+// eventIndexKey returns the key of an event index entry. The event sequence
+// follows the transaction ID so that each of its events has its own entry.
+func eventIndexKey(attribute []byte, height uint64, trxID []byte, sequence uint32) []byte {
+	key := indexKey(eventIndexNamespace, attribute, height, trxID)
+	key = append(key, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(key[len(key)-4:], sequence)
+	return key
+}
+
+// nonceIndexKey returns the key of a nonce index entry. Entries of an account
+// are ordered by nonce rather than height so that gaps can be found.
+func nonceIndexKey(account []byte, nonce uint64, trxID []byte) []byte {
+	key := append(indexPrefix(nonceIndexNamespace, account), heightBytes(nonce)...)
+	return append(key, trxID...)
+}
+
+// usageKey returns the key of an account's resource usage in the bucket starting at the given height
+func usageKey(account []byte, bucketHeight uint64) []byte {
+	return append(usagePrefix(account), heightBytes(bucketHeight)...)
+}
+
+// usagePrefix returns the prefix shared by every resource usage bucket of an account
+func usagePrefix(account []byte) []byte {
+	return auxiliaryKey(usageNamespace, protowire.AppendVarint(nil, uint64(len(account))), account)
+}
+
+// outboxKey returns the key of an outbox entry
+func outboxKey(id uint64) []byte {
+	return auxiliaryKey(outboxNamespace, heightBytes(id))
+}
+
+// containingBlockPrefix returns the prefix shared by the containing blocks of a transaction
+func containingBlockPrefix(trxID []byte) []byte {
+	return indexPrefix(containingBlockNamespace, trxID)
+}
+
+// containingBlockKey returns the key of the containing block of a transaction with the given sequence
+func containingBlockKey(trxID []byte, sequence uint32) []byte {
+	key := append(containingBlockPrefix(trxID), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(key[len(key)-4:], sequence)
+	return key
+}
+
+// heightIndexKey returns the key of a transaction's height index entry
+func heightIndexKey(height uint64, trxID []byte) []byte {
+	return indexKey(heightIndexNamespace, nil, height, trxID)
+}
+
+// blockTimeKey returns the key of the height of the block recorded at the given timestamp
+func blockTimeKey(timestamp uint64) []byte {
+	return auxiliaryKey(blockTimeIndexNamespace, heightBytes(timestamp))
+}
+
+// legacyBlockTimeKey returns the key of the timestamp of the block at the given height, before schema version 5
+func legacyBlockTimeKey(height uint64) []byte {
+	return auxiliaryKey(blockTimeNamespace, heightBytes(height))
+}
+
+// metadataKey returns the key of the metadata record with the given name
+func metadataKey(name string) []byte {
+	return auxiliaryKey(metadataNamespace, []byte(name))
+}
+
+// heightBytes encodes a height so that byte order matches numeric order
+func heightBytes(height uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+	return b
+}
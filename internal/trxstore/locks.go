@@ -0,0 +1,53 @@
+package trxstore
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// lockStripes is the number of mutexes keys are spread over
+const lockStripes = 256
+
+// stripedMutex serializes writers of the same key without serializing writers
+// of different keys. Keys are hashed onto a fixed set of mutexes, so unrelated
+// keys occasionally share one.
+type stripedMutex struct {
+	stripes [lockStripes]sync.Mutex
+}
+
+// stripe returns the index of the mutex a key is hashed onto
+func stripe(key []byte) int {
+	h := fnv.New32a()
+	h.Write(key)
+	return int(h.Sum32() % lockStripes)
+}
+
+// lock locks the mutex of the given key and returns it so that it can be unlocked
+func (m *stripedMutex) lock(key []byte) *sync.Mutex {
+	mutex := &m.stripes[stripe(key)]
+	mutex.Lock()
+	return mutex
+}
+
+// lockAll locks the mutexes of every given key and returns a function unlocking
+// them. Mutexes are locked in a fixed order so that callers cannot deadlock.
+func (m *stripedMutex) lockAll(keys [][]byte) func() {
+	var locked [lockStripes]bool
+	for _, key := range keys {
+		locked[stripe(key)] = true
+	}
+
+	for i := range locked {
+		if locked[i] {
+			m.stripes[i].Lock()
+		}
+	}
+
+	return func() {
+		for i := range locked {
+			if locked[i] {
+				m.stripes[i].Unlock()
+			}
+		}
+	}
+}
@@ -3,20 +3,27 @@ package trxstore
 import (
 	"encoding/hex"
 	"errors"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // MapBackend implements a key-value store backed by a simple map
 type MapBackend struct {
 	storage map[string][]byte
+	mutex   sync.RWMutex
 }
 
 // NewMapBackend creates and returns a reference to a map backend instance
 func NewMapBackend() *MapBackend {
-	return &MapBackend{make(map[string][]byte)}
+	return &MapBackend{storage: make(map[string][]byte)}
 }
 
 // Reset resets the database
 func (backend *MapBackend) Reset() error {
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+
 	backend.storage = make(map[string][]byte)
 	return nil
 }
@@ -31,10 +38,59 @@ func (backend *MapBackend) Put(key []byte, value []byte) error {
 	}
 	k := hex.EncodeToString(key)
 	//fmt.Println("Putting key:", k)
+	backend.mutex.Lock()
 	backend.storage[k] = value
+	backend.mutex.Unlock()
+	return nil
+}
+
+// Delete removes the requested value from the database
+func (backend *MapBackend) Delete(key []byte) error {
+	backend.mutex.Lock()
+	delete(backend.storage, hex.EncodeToString(key))
+	backend.mutex.Unlock()
 	return nil
 }
 
+// NewWriteBatch creates a batch that is applied to the map under a single lock
+func (backend *MapBackend) NewWriteBatch() WriteBatch {
+	return &mapWriteBatch{backend: backend}
+}
+
+type mapWriteBatch struct {
+	backend *MapBackend
+	keys    []string
+	values  [][]byte
+}
+
+func (wb *mapWriteBatch) Put(key []byte, value []byte) error {
+	if key == nil {
+		return errors.New("Cannot put a nil value key")
+	}
+	if value == nil {
+		return errors.New("Cannot put a nil value")
+	}
+	wb.keys = append(wb.keys, hex.EncodeToString(key))
+	wb.values = append(wb.values, value)
+	return nil
+}
+
+func (wb *mapWriteBatch) Flush() error {
+	wb.backend.mutex.Lock()
+	defer wb.backend.mutex.Unlock()
+
+	for i, k := range wb.keys {
+		wb.backend.storage[k] = wb.values[i]
+	}
+	wb.Cancel()
+	return nil
+}
+
+func (wb *mapWriteBatch) Cancel() {
+	wb.keys = nil
+	wb.values = nil
+}
+
 // Get fetches the requested value from the database
 func (backend *MapBackend) Get(key []byte) ([]byte, error) {
 	if len(key) == 0 {
@@ -42,10 +98,135 @@ func (backend *MapBackend) Get(key []byte) ([]byte, error) {
 	}
 	k := hex.EncodeToString(key)
 	//fmt.Println("Getting key:", k)
+	backend.mutex.RLock()
 	val, ok := backend.storage[k]
+	backend.mutex.RUnlock()
 	if ok {
 		return val, nil
 	}
 
 	return make([]byte, 0), nil
 }
+
+// GetMany fetches the requested values from the database
+func (backend *MapBackend) GetMany(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+
+	backend.mutex.RLock()
+	defer backend.mutex.RUnlock()
+
+	for i, key := range keys {
+		if len(key) == 0 {
+			return nil, errors.New("Key cannot be empty")
+		}
+		if val, ok := backend.storage[hex.EncodeToString(key)]; ok {
+			values[i] = val
+		} else {
+			values[i] = make([]byte, 0)
+		}
+	}
+
+	return values, nil
+}
+
+// GetManyWithPrefixes fetches the requested values and lists the values with the given key prefixes
+// under the same lock. The lock is not held while f is called, so f may access the backend.
+func (backend *MapBackend) GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error) {
+	type pair struct {
+		key   string
+		value []byte
+	}
+
+	values := make([][]byte, len(keys))
+	pairs := make([][]pair, len(prefixes))
+
+	backend.mutex.RLock()
+	for i, key := range keys {
+		if len(key) == 0 {
+			backend.mutex.RUnlock()
+			return nil, errors.New("Key cannot be empty")
+		}
+		if val, ok := backend.storage[hex.EncodeToString(key)]; ok {
+			values[i] = val
+		} else {
+			values[i] = make([]byte, 0)
+		}
+	}
+	for i, prefix := range prefixes {
+		p := hex.EncodeToString(prefix)
+		for k, v := range backend.storage {
+			if strings.HasPrefix(k, p) {
+				pairs[i] = append(pairs[i], pair{key: k, value: v})
+			}
+		}
+	}
+	backend.mutex.RUnlock()
+
+	for i := range pairs {
+		sort.Slice(pairs[i], func(a, b int) bool { return pairs[i][a].key < pairs[i][b].key })
+		for _, p := range pairs[i] {
+			key, err := hex.DecodeString(p.key)
+			if err != nil {
+				return nil, err
+			}
+			if err = f(i, key, p.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// Iterate visits the stored values with the given key prefix in key order
+func (backend *MapBackend) Iterate(prefix []byte, f func(key []byte, value []byte) error) error {
+	return backend.IterateFrom(prefix, nil, false, f)
+}
+
+// IterateFrom visits the stored values with the given key prefix in key order, beginning at start.
+// The lock is not held while f is called, so f may access the backend.
+func (backend *MapBackend) IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error {
+	// Lowercase hex encoding preserves byte ordering, so sorting the encoded keys suffices
+	p := hex.EncodeToString(prefix)
+	keys := make([]string, 0)
+	backend.mutex.RLock()
+	for k := range backend.storage {
+		if strings.HasPrefix(k, p) {
+			keys = append(keys, k)
+		}
+	}
+	backend.mutex.RUnlock()
+	sort.Strings(keys)
+
+	if reverse {
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	s := hex.EncodeToString(start)
+	for _, k := range keys {
+		if start != nil && ((!reverse && k < s) || (reverse && k > s)) {
+			continue
+		}
+
+		key, err := hex.DecodeString(k)
+		if err != nil {
+			return err
+		}
+
+		backend.mutex.RLock()
+		value, ok := backend.storage[k]
+		backend.mutex.RUnlock()
+
+		// Keys deleted since they were listed are skipped
+		if !ok {
+			continue
+		}
+		if err = f(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package trxstore
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	util "github.com/koinos/koinos-util-golang/v2"
+)
+
+// NonceEntry is a nonce used by an account and the transaction that used it
+type NonceEntry struct {
+	Nonce         uint64
+	TransactionID []byte
+}
+
+// NoncePage is a page of nonces returned by a nonce query
+type NoncePage struct {
+	Nonces []*NonceEntry
+
+	// Cursor continues the query with the next page, nil if there are no more nonces
+	Cursor []byte
+}
+
+// nonceAccount returns the account whose nonce a transaction uses and the
+// nonce value. The payee's nonce is used when the transaction has one,
+// otherwise the payer's. Returns false if the transaction has no valid nonce.
+func nonceAccount(tx *protocol.Transaction) ([]byte, uint64, bool) {
+	header := tx.GetHeader()
+	if header == nil || len(header.Nonce) == 0 {
+		return nil, 0, false
+	}
+
+	nonce, err := util.NonceBytesToUInt64(header.Nonce)
+	if err != nil {
+		return nil, 0, false
+	}
+
+	if len(header.Payee) > 0 {
+		return header.Payee, nonce, true
+	}
+	if len(header.Payer) > 0 {
+		return header.Payer, nonce, true
+	}
+
+	return nil, 0, false
+}
+
+// GetTransactionsByNonce returns the transactions that used the given nonce of an account. More
+// than one transaction is returned if transactions with the same nonce were included in different forks.
+func (handler *TransactionStore) GetTransactionsByNonce(account []byte, nonce uint64) ([]*transaction_store.TransactionItem, error) {
+	prefix := append(indexPrefix(nonceIndexNamespace, account), heightBytes(nonce)...)
+	trxIDs := make([][]byte, 0, 1)
+
+	err := handler.backend.Iterate(prefix, func(key []byte, value []byte) error {
+		trxIDs = append(trxIDs, value)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return handler.GetTransactionsByID(trxIDs)
+}
+
+// GetNonces returns a page of the nonces used by an account, ordered by nonce
+func (handler *TransactionStore) GetNonces(account []byte, page *Pagination) (*NoncePage, error) {
+	prefix := indexPrefix(nonceIndexNamespace, account)
+	result := &NoncePage{Nonces: make([]*NonceEntry, 0)}
+
+	cursor, err := handler.iterateIndex(prefix, page, func(key []byte, value []byte) error {
+		result.Nonces = append(result.Nonces, &NonceEntry{
+			Nonce:         binary.BigEndian.Uint64(key[len(prefix):]),
+			TransactionID: value,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Cursor = cursor
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package trxstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+)
+
+// OperationType identifies the kind of an operation. Its values are stored in
+// index keys and must not be renumbered.
+type OperationType byte
+
+// Operation types
+const (
+	UploadContractOperation OperationType = iota + 1
+	CallContractOperation
+	SetSystemCallOperation
+	SetSystemContractOperation
+)
+
+// ErrUnknownOperationType occurs when an operation type name is not recognized
+var ErrUnknownOperationType = errors.New("unknown operation type")
+
+var operationTypeNames = map[OperationType]string{
+	UploadContractOperation:    "upload_contract",
+	CallContractOperation:      "call_contract",
+	SetSystemCallOperation:     "set_system_call",
+	SetSystemContractOperation: "set_system_contract",
+}
+
+// String returns the name of the operation type as it appears in protocol.Operation
+func (opType OperationType) String() string {
+	if name, ok := operationTypeNames[opType]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("operation_type(%d)", byte(opType))
+}
+
+// ParseOperationType returns the operation type with the given name
+func ParseOperationType(name string) (OperationType, error) {
+	for opType, opName := range operationTypeNames {
+		if opName == name {
+			return opType, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s", ErrUnknownOperationType, name)
+}
+
+// operationType classifies an operation, returning 0 if its type is not known
+func operationType(op *protocol.Operation) OperationType {
+	switch op.Op.(type) {
+	case *protocol.Operation_UploadContract:
+		return UploadContractOperation
+	case *protocol.Operation_CallContract:
+		return CallContractOperation
+	case *protocol.Operation_SetSystemCall:
+		return SetSystemCallOperation
+	case *protocol.Operation_SetSystemContract:
+		return SetSystemContractOperation
+	}
+
+	return 0
+}
@@ -0,0 +1,134 @@
+package trxstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/koinos/koinos-transaction-store/internal/trxstore/storepb"
+	"google.golang.org/protobuf/proto"
+)
+
+// OutboxEntry is a notification waiting to be delivered to a destination.
+// Entries are stored so that deliveries survive restarts.
+type OutboxEntry struct {
+	// ID is assigned when the entry is added and orders entries by age
+	ID uint64
+
+	Destination string
+	Payload     []byte
+	Attempts    uint32
+
+	// NextAttempt is the Unix time in milliseconds before which delivery is not retried
+	NextAttempt int64
+}
+
+// outboxSequence assigns the IDs of outbox entries
+type outboxSequence struct {
+	mutex sync.Mutex
+
+	// next is the ID of the next outbox entry, 0 until it has been read from the backend
+	next uint64
+}
+
+// marshal encodes the entry, without its ID which is part of the key, as storepb.OutboxEntry
+func (entry *OutboxEntry) marshal() []byte {
+	buf, _ := proto.Marshal(&storepb.OutboxEntry{
+		Destination: entry.Destination,
+		Payload:     entry.Payload,
+		Attempts:    entry.Attempts,
+		NextAttempt: entry.NextAttempt,
+	})
+	return buf
+}
+
+func unmarshalOutboxEntry(buf []byte) (*OutboxEntry, error) {
+	message := &storepb.OutboxEntry{}
+	if err := proto.Unmarshal(buf, message); err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+	}
+
+	return &OutboxEntry{
+		Destination: message.Destination,
+		Payload:     message.Payload,
+		Attempts:    message.Attempts,
+		NextAttempt: message.NextAttempt,
+	}, nil
+}
+
+// AddOutboxEntries adds entries to the outbox, assigning their IDs
+func (handler *TransactionStore) AddOutboxEntries(entries []*OutboxEntry) error {
+	sequence := handler.outbox
+	sequence.mutex.Lock()
+	defer sequence.mutex.Unlock()
+
+	// The next ID follows the newest entry, found once and then counted in memory
+	if sequence.next == 0 {
+		sequence.next = 1
+		prefix := auxiliaryKey(outboxNamespace)
+		err := handler.backend.IterateFrom(prefix, nil, true, func(key []byte, value []byte) error {
+			sequence.next = binary.BigEndian.Uint64(key[len(prefix):]) + 1
+			return errPageFull
+		})
+		if err != nil && !errors.Is(err, errPageFull) {
+			sequence.next = 0
+			return fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+	}
+
+	for _, entry := range entries {
+		entry.ID = sequence.next
+		if err := handler.backend.Put(outboxKey(entry.ID), entry.marshal()); err != nil {
+			return fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+		sequence.next++
+	}
+
+	return nil
+}
+
+// GetOutboxEntries returns up to limit of the oldest outbox entries with an ID greater than afterID
+func (handler *TransactionStore) GetOutboxEntries(afterID uint64, limit int) ([]*OutboxEntry, error) {
+	prefix := auxiliaryKey(outboxNamespace)
+	entries := make([]*OutboxEntry, 0)
+
+	err := handler.backend.IterateFrom(prefix, outboxKey(afterID+1), false, func(key []byte, value []byte) error {
+		if len(entries) == limit {
+			return errPageFull
+		}
+
+		entry, err := unmarshalOutboxEntry(value)
+		if err != nil {
+			return err
+		}
+		entry.ID = binary.BigEndian.Uint64(key[len(prefix):])
+		entries = append(entries, entry)
+		return nil
+	})
+	if errors.Is(err, ErrDeserialization) {
+		return nil, err
+	} else if err != nil && !errors.Is(err, errPageFull) {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return entries, nil
+}
+
+// UpdateOutboxEntry stores the delivery state of an outbox entry
+func (handler *TransactionStore) UpdateOutboxEntry(entry *OutboxEntry) error {
+	if err := handler.backend.Put(outboxKey(entry.ID), entry.marshal()); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// RemoveOutboxEntry removes a delivered or abandoned outbox entry
+func (handler *TransactionStore) RemoveOutboxEntry(id uint64) error {
+	if err := handler.backend.Delete(outboxKey(id)); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package trxstore
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestOutbox(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		entries := []*OutboxEntry{
+			{Destination: "a", Payload: []byte("1")},
+			{Destination: "b", Payload: []byte("2")},
+			{Destination: "a", Payload: []byte("3")},
+		}
+		if err := store.AddOutboxEntries(entries); err != nil {
+			t.Fatal(err)
+		}
+		for i, entry := range entries {
+			if entry.ID != uint64(i+1) {
+				t.Fatalf("Expected ID %v, got %v", i+1, entry.ID)
+			}
+		}
+
+		entries[1].Attempts = 2
+		entries[1].NextAttempt = 1234
+		if err := store.UpdateOutboxEntry(entries[1]); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.RemoveOutboxEntry(entries[0].ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.RemoveOutboxEntry(100); err != nil {
+			t.Fatal("Removing a missing entry should not give an error: ", err)
+		}
+
+		result, err := store.GetOutboxEntries(0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result) != 2 || result[0].ID != 2 || result[1].ID != 3 {
+			t.Fatalf("Unexpected outbox entries %+v", result)
+		}
+		if result[0].Destination != "b" || !bytes.Equal(result[0].Payload, []byte("2")) || result[0].Attempts != 2 || result[0].NextAttempt != 1234 {
+			t.Fatalf("Unexpected outbox entry %+v", result[0])
+		}
+
+		// Entries are paged by ID
+		result, err = store.GetOutboxEntries(2, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result) != 1 || result[0].ID != 3 {
+			t.Fatalf("Unexpected outbox entries %+v", result)
+		}
+
+		// A store reopened on the backend continues after the newest entry
+		store = NewTransactionStore(b)
+		entry := &OutboxEntry{Destination: "c"}
+		if err := store.AddOutboxEntries([]*OutboxEntry{entry}); err != nil {
+			t.Fatal(err)
+		}
+		if entry.ID != 4 {
+			t.Fatalf("Expected ID 4, got %v", entry.ID)
+		}
+
+		verified, err := store.Verify(func(problem *VerificationProblem) {
+			t.Errorf("Unexpected problem: %+v", problem)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verified.AuxiliaryRecords != 3 {
+			t.Fatalf("Expected 3 auxiliary records, got %v", verified.AuxiliaryRecords)
+		}
+
+		CloseBackend(b)
+	}
+
+	store := NewTransactionStore(&ErrorBackend{})
+	if err := store.AddOutboxEntries([]*OutboxEntry{{}}); !errors.Is(err, ErrBackend) {
+		t.Fatal("Expected ErrBackend, got ", err)
+	}
+	if _, err := store.GetOutboxEntries(0, 10); !errors.Is(err, ErrBackend) {
+		t.Fatal("Expected ErrBackend, got ", err)
+	}
+	if err := store.RemoveOutboxEntry(1); !errors.Is(err, ErrBackend) {
+		t.Fatal("Expected ErrBackend, got ", err)
+	}
+}
@@ -0,0 +1,298 @@
+package trxstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Transactions are pruned oldest first using the height index, which holds an
+// entry for every transaction at the height of the latest block including it.
+// Including a transaction again at a greater height adds an entry at that
+// height, superseding the previous one, which pruning removes. The records of
+// a transaction are deleted in an order that lets an interrupted prune be
+// completed by the next one: the records derived from others first, then the
+// transaction record, and the height index entry that finds it last. Resource
+// usage buckets aggregate many transactions and are kept, and so are the block times at the start of each usage
+// bucket, which usage is queried by time with.
+
+// PutBlockTime records the timestamp, in milliseconds, of the block at the given height. Unless
+// every block time is recorded, only the times of blocks beginning a resource usage bucket are.
+func (handler *TransactionStore) PutBlockTime(height uint64, timestamp uint64) error {
+	if !handler.everyBlockTime && height%UsageBucketSize != 0 {
+		return nil
+	}
+
+	if err := handler.backend.Put(blockTimeKey(timestamp), protowire.AppendVarint(nil, height)); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// HeightAt returns the height of the first recorded block with a timestamp, in milliseconds,
+// at or after the given one, or the height following the last recorded block if every block is
+// older. Returns 0 if no block time has been recorded.
+func (handler *TransactionStore) HeightAt(timestamp uint64) (uint64, error) {
+	height, found, err := handler.firstBlockTime(blockTimeKey(timestamp), false)
+	if err != nil || found {
+		return height, err
+	}
+
+	height, found, err = handler.firstBlockTime(nil, true)
+	if err != nil || !found {
+		return 0, err
+	}
+
+	return height + 1, nil
+}
+
+// HeightBefore returns the height of the last recorded block with a timestamp, in milliseconds,
+// at or before the given one, or 0 if every recorded block is newer or none has been recorded
+func (handler *TransactionStore) HeightBefore(timestamp uint64) (uint64, error) {
+	height, _, err := handler.firstBlockTime(blockTimeKey(timestamp), true)
+	return height, err
+}
+
+// firstBlockTime returns the height of the first block time visited from start, and whether there is one
+func (handler *TransactionStore) firstBlockTime(start []byte, reverse bool) (uint64, bool, error) {
+	height := uint64(0)
+	found := false
+	var callbackErr error
+
+	err := handler.backend.IterateFrom(auxiliaryKey(blockTimeIndexNamespace), start, reverse, func(key []byte, value []byte) error {
+		height, _, callbackErr = parseBlockTime(key, value)
+		if callbackErr != nil {
+			return callbackErr
+		}
+		found = true
+		return errStopIteration
+	})
+	if callbackErr != nil {
+		return 0, false, callbackErr
+	}
+	if err != nil && !errors.Is(err, errStopIteration) {
+		return 0, false, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return height, found, nil
+}
+
+// parseBlockTime returns the height and timestamp of a block time record
+func parseBlockTime(key []byte, value []byte) (uint64, uint64, error) {
+	if len(key) != len(blockTimeKey(0)) {
+		return 0, 0, fmt.Errorf("%w, malformed block time key 0x%x", ErrDeserialization, key)
+	}
+
+	height, n := protowire.ConsumeVarint(value)
+	if n < 0 || n != len(value) {
+		return 0, 0, fmt.Errorf("%w, malformed block time 0x%x", ErrDeserialization, value)
+	}
+
+	return height, binary.BigEndian.Uint64(key[len(key)-8:]), nil
+}
+
+// Prune deletes up to limit transactions whose latest containing block is below the given height,
+// oldest first, with their receipts, index entries and containing blocks, and the block times below
+// that height. Returns the number of transactions deleted, which is less than limit once every
+// transaction below the height has been deleted.
+func (handler *TransactionStore) Prune(height uint64, limit int) (uint64, error) {
+	end := heightIndexKey(height, nil)
+	count := uint64(0)
+
+	for int(count) < limit {
+		keys := make([][]byte, 0)
+		err := handler.backend.Iterate(indexPrefix(heightIndexNamespace, nil), func(key []byte, value []byte) error {
+			if len(keys) == limit-int(count) || bytes.Compare(key, end) >= 0 {
+				return errEndOfRange
+			}
+			keys = append(keys, key)
+			return nil
+		})
+		if err != nil && !errors.Is(err, errEndOfRange) {
+			return count, fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		for _, key := range keys {
+			// Height index entries end with the height and the transaction ID, following the prefix like end
+			pruned, err := handler.pruneTransaction(key, binary.BigEndian.Uint64(key[len(end)-8:]), key[len(end):])
+			if err != nil {
+				return count, err
+			}
+			if pruned {
+				count++
+			}
+		}
+	}
+
+	return count, handler.pruneBlockTimes(height)
+}
+
+// pruneTransaction deletes every record of the transaction found by the given height index entry,
+// or only the entry if it has been superseded by a later inclusion. Returns true if the transaction
+// was deleted.
+func (handler *TransactionStore) pruneTransaction(heightKey []byte, height uint64, trxID []byte) (bool, error) {
+	defer handler.itemLocks.lock(trxID).Unlock()
+
+	record, err := handler.getIndexRecord(trxID)
+	if err != nil && !errors.Is(err, ErrDeserialization) {
+		return false, err
+	}
+
+	if record != nil && height < record.retainedHeight() {
+		if err := handler.backend.Delete(heightKey); err != nil {
+			return false, fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+		return false, nil
+	}
+
+	item, err := handler.getRecord(trxID)
+	if err != nil && !errors.Is(err, ErrDeserialization) {
+		return false, err
+	}
+
+	receipt, err := handler.getReceipt(trxID)
+	if err != nil && !errors.Is(err, ErrDeserialization) {
+		return false, err
+	}
+
+	keys := make([][]byte, 0)
+	if item != nil && item.Transaction != nil && record != nil {
+		for _, entry := range indexEntries(item.Transaction, record, receipt) {
+			if !bytes.Equal(entry, heightKey) {
+				keys = append(keys, entry)
+			}
+		}
+	}
+
+	err = handler.backend.Iterate(containingBlockPrefix(trxID), func(key []byte, value []byte) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	keys = append(keys, receiptKey(trxID), indexRecordKey(trxID), trxID, heightKey)
+	for _, key := range keys {
+		if err := handler.backend.Delete(key); err != nil {
+			return false, fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+		if handler.cache != nil && bytes.Equal(key, trxID) {
+			handler.cache.remove(trxID)
+		}
+	}
+
+	return true, nil
+}
+
+// retainUntil indexes a transaction included again at the given height at that height for pruning,
+// if it is above every block previously including it. Malformed index records are left for Verify
+// to report.
+func (handler *TransactionStore) retainUntil(trxID []byte, height uint64) error {
+	record, err := handler.getIndexRecord(trxID)
+	if errors.Is(err, ErrDeserialization) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if record == nil || height <= record.retainedHeight() {
+		return nil
+	}
+
+	// The previous entry is superseded once the record is updated, and removed by pruning
+	record.lastHeight = height
+	if err := handler.backend.Put(heightIndexKey(height, trxID), trxID); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+	if err := handler.backend.Put(indexRecordKey(trxID), record.marshal()); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// pruneBlockTimes deletes the block times below the given height, except those beginning a resource
+// usage bucket. Block times are visited in the order of their timestamps, which is that of their heights.
+func (handler *TransactionStore) pruneBlockTimes(height uint64) error {
+	keys := make([][]byte, 0)
+	var callbackErr error
+
+	err := handler.backend.Iterate(auxiliaryKey(blockTimeIndexNamespace), func(key []byte, value []byte) error {
+		var blockHeight uint64
+		if blockHeight, _, callbackErr = parseBlockTime(key, value); callbackErr != nil {
+			return callbackErr
+		}
+		if blockHeight >= height {
+			return errEndOfRange
+		}
+		if blockHeight%UsageBucketSize != 0 {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if callbackErr != nil {
+		return callbackErr
+	}
+	if err != nil && !errors.Is(err, errEndOfRange) {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	for _, key := range keys {
+		if err := handler.backend.Delete(key); err != nil {
+			return fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateHeightIndex adds the height index entry of a transaction indexed before transactions were indexed by height.
+// Transactions stored before they were indexed at all have no index record, they are indexed by migrateIndexes.
+func (handler *TransactionStore) migrateHeightIndex(key []byte, value []byte) error {
+	prefix := auxiliaryKey(indexRecordNamespace)
+	if !bytes.HasPrefix(key, prefix) {
+		return nil
+	}
+
+	// Malformed index records are left for Verify to report
+	record, err := unmarshalIndexRecord(value)
+	if err != nil {
+		return nil
+	}
+
+	trxID := key[len(prefix):]
+	if err := handler.backend.Put(heightIndexKey(record.height, trxID), trxID); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// migrateBlockTimes moves a block time recorded by height to the index of block times by timestamp
+func (handler *TransactionStore) migrateBlockTimes(key []byte, value []byte) error {
+	if !bytes.HasPrefix(key, auxiliaryKey(blockTimeNamespace)) {
+		return nil
+	}
+
+	// Malformed block times are dropped, they could not be read either
+	timestamp, n := protowire.ConsumeVarint(value)
+	if len(key) == len(legacyBlockTimeKey(0)) && n == len(value) {
+		height := binary.BigEndian.Uint64(key[len(key)-8:])
+		if err := handler.backend.Put(blockTimeKey(timestamp), protowire.AppendVarint(nil, height)); err != nil {
+			return fmt.Errorf("%w, %v", ErrBackend, err)
+		}
+	}
+
+	if err := handler.backend.Delete(key); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
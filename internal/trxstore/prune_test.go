@@ -0,0 +1,295 @@
+package trxstore
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	util "github.com/koinos/koinos-util-golang/v2"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// makePrunableTransactions returns signed transactions with receipts included at heights 1 to 5
+func makePrunableTransactions(t *testing.T) []*IncludedTransaction {
+	key, err := util.GenerateKoinosKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	payer := key.AddressBytes()
+
+	trxs := make([]*IncludedTransaction, 0)
+	for i := uint64(1); i <= 5; i++ {
+		nonce, err := util.UInt64ToNonceBytes(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := makeSignedTransaction(t, &protocol.TransactionHeader{Payer: payer, RcLimit: i, Nonce: nonce}, key)
+		tx.Operations = []*protocol.Operation{{Op: &protocol.Operation_CallContract{CallContract: &protocol.CallContractOperation{ContractId: []byte{1}, EntryPoint: 2}}}}
+		receipt := &protocol.TransactionReceipt{
+			Id:     tx.Id,
+			Payer:  payer,
+			RcUsed: i,
+			Events: []*protocol.EventData{{Sequence: 0, Source: []byte{1}, Name: "transfer"}},
+		}
+		trxs = append(trxs, &IncludedTransaction{Transaction: tx, Receipt: receipt, Topology: &koinos.BlockTopology{Id: []byte{byte(i)}, Height: i}})
+	}
+
+	return trxs
+}
+
+func addPrunableTransactions(t *testing.T, store *TransactionStore, trxs []*IncludedTransaction) {
+	for _, trx := range trxs {
+		if err := store.AddIncludedTransactionWithReceipt(trx.Transaction, trx.Receipt, trx.Topology); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.PutBlockTime(trx.Topology.Height, trx.Topology.Height*1000); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHeightAt(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b, WithEveryBlockTime(true))
+
+		height, err := store.HeightAt(1000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != 0 {
+			t.Fatalf("Expected height 0 without block times, got %v", height)
+		}
+
+		for h := uint64(1); h <= 5; h++ {
+			if err := store.PutBlockTime(h, h*1000); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for timestamp, expected := range map[uint64]uint64{0: 1, 1000: 1, 2500: 3, 5000: 5, 10000: 6} {
+			height, err := store.HeightAt(timestamp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if height != expected {
+				t.Fatalf("Expected height %v at %v, got %v", expected, timestamp, height)
+			}
+		}
+
+		for timestamp, expected := range map[uint64]uint64{0: 0, 1000: 1, 2500: 2, 5000: 5, 10000: 5} {
+			height, err := store.HeightBefore(timestamp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if height != expected {
+				t.Fatalf("Expected height %v before %v, got %v", expected, timestamp, height)
+			}
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestBucketBlockTimes(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		// Only the times of blocks beginning a usage bucket are recorded by default
+		for _, h := range []uint64{UsageBucketSize - 1, UsageBucketSize, UsageBucketSize + 1, 2 * UsageBucketSize} {
+			if err := store.PutBlockTime(h, h*1000); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		count := 0
+		if err := b.Iterate(auxiliaryKey(blockTimeIndexNamespace), func(key []byte, value []byte) error {
+			count++
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Fatalf("Expected 2 block times, got %v", count)
+		}
+
+		height, err := store.HeightAt(UsageBucketSize*1000 + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if height != 2*UsageBucketSize {
+			t.Fatalf("Expected height %v, got %v", 2*UsageBucketSize, height)
+		}
+
+		// The previous recorded block is found between bucket boundaries
+		if height, err = store.HeightBefore(UsageBucketSize*1000 + 1); err != nil {
+			t.Fatal(err)
+		}
+		if height != UsageBucketSize {
+			t.Fatalf("Expected height %v, got %v", UsageBucketSize, height)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	trxs := makePrunableTransactions(t)
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b, WithCacheBytes(1<<20), WithEveryBlockTime(true))
+		addPrunableTransactions(t, store, trxs)
+
+		// Included again above the pruned height, the latest inclusion keeps the transaction
+		reincluded := &koinos.BlockTopology{Id: []byte{9}, Height: 9}
+		if err := store.AddIncludedTransaction(trxs[0].Transaction, reincluded); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.GetTransactionsByID([][]byte{trxs[0].Transaction.Id}); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, expected := range []uint64{1, 0} {
+			count, err := store.Prune(3, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != expected {
+				t.Fatalf("Expected %v pruned transactions, got %v", expected, count)
+			}
+		}
+
+		items, err := store.GetTransactionsByID([][]byte{trxs[0].Transaction.Id, trxs[1].Transaction.Id, trxs[2].Transaction.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 2 || !bytes.Equal(items[0].Transaction.Id, trxs[0].Transaction.Id) || !bytes.Equal(items[1].Transaction.Id, trxs[2].Transaction.Id) {
+			t.Fatalf("Expected only the unpruned transactions, got %v", items)
+		}
+
+		// Only the resource usage of the pruned transactions is left behind, and the superseded
+		// height index entry of the transaction included again is removed
+		expected := NewBackend(bType)
+		expectedStore := NewTransactionStore(expected, WithEveryBlockTime(true))
+		addPrunableTransactions(t, expectedStore, []*IncludedTransaction{trxs[0]})
+		addPrunableTransactions(t, expectedStore, trxs[2:])
+		if err := expectedStore.AddIncludedTransaction(trxs[0].Transaction, reincluded); err != nil {
+			t.Fatal(err)
+		}
+		if err := expected.Delete(heightIndexKey(1, trxs[0].Transaction.Id)); err != nil {
+			t.Fatal(err)
+		}
+		if err := expected.Delete(blockTimeKey(1000)); err != nil {
+			t.Fatal(err)
+		}
+
+		expectedRecords := dumpBackend(t, expected)
+		records := dumpBackend(t, b)
+		usage := auxiliaryKey(usageNamespace)
+		for k, v := range records {
+			if !bytes.HasPrefix([]byte(k), usage) && !bytes.Equal(expectedRecords[k], v) {
+				t.Fatalf("Unexpected record 0x%x", k)
+			}
+		}
+		for k := range expectedRecords {
+			if _, ok := records[k]; !ok {
+				t.Fatalf("Missing record 0x%x", k)
+			}
+		}
+
+		if _, err := store.Verify(func(problem *VerificationProblem) {
+			t.Error("Unexpected problem: ", problem)
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Once its latest inclusion is below the pruned height, the transaction included again is pruned
+		count, err := store.Prune(10, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 4 {
+			t.Fatalf("Expected 4 pruned transactions, got %v", count)
+		}
+
+		CloseBackend(expected)
+		CloseBackend(b)
+	}
+}
+
+func TestMigrateHeightIndex(t *testing.T) {
+	trxs := makePrunableTransactions(t)
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+		addPrunableTransactions(t, store, trxs)
+
+		// A database written before transactions were indexed by height
+		for _, trx := range trxs {
+			if err := b.Delete(heightIndexKey(trx.Topology.Height, trx.Transaction.Id)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := store.putSchemaVersion(3); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.Migrate(func(version uint64, description string) {}); err != nil {
+			t.Fatal(err)
+		}
+
+		count, err := store.Prune(6, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != uint64(len(trxs)) {
+			t.Fatalf("Expected %v pruned transactions, got %v", len(trxs), count)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestMigrateBlockTimes(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		// A database written before block times were indexed by timestamp
+		for h := uint64(1); h <= 5; h++ {
+			if err := b.Put(legacyBlockTimeKey(h), protowire.AppendVarint(nil, h*1000)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := store.putSchemaVersion(4); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.Migrate(func(version uint64, description string) {}); err != nil {
+			t.Fatal(err)
+		}
+
+		for timestamp, expected := range map[uint64]uint64{1000: 1, 2500: 3, 10000: 6} {
+			height, err := store.HeightAt(timestamp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if height != expected {
+				t.Fatalf("Expected height %v at %v, got %v", expected, timestamp, height)
+			}
+		}
+
+		if err := b.Iterate(auxiliaryKey(blockTimeNamespace), func(key []byte, value []byte) error {
+			return fmt.Errorf("block time 0x%x was not migrated", key)
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		CloseBackend(b)
+	}
+}
@@ -0,0 +1,44 @@
+package trxstore
+
+import (
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"google.golang.org/protobuf/proto"
+)
+
+// putReceipt stores the receipt of a transaction. State delta entries are
+// dropped, they duplicate chain state and are not used by any index.
+func (handler *TransactionStore) putReceipt(receipt *protocol.TransactionReceipt) error {
+	stored := proto.Clone(receipt).(*protocol.TransactionReceipt)
+	stored.StateDeltaEntries = nil
+
+	receiptBytes, err := proto.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrSerialization, err)
+	}
+
+	if err := handler.backend.Put(receiptKey(receipt.Id), receiptBytes); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// getReceipt returns the receipt of a transaction, or nil if it has none
+func (handler *TransactionStore) getReceipt(trxID []byte) (*protocol.TransactionReceipt, error) {
+	receiptBytes, err := handler.backend.Get(receiptKey(trxID))
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+	if len(receiptBytes) == 0 {
+		return nil, nil
+	}
+
+	receipt := &protocol.TransactionReceipt{}
+	if err := proto.Unmarshal(receiptBytes, receipt); err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+	}
+
+	return receipt, nil
+}
@@ -0,0 +1,242 @@
+package trxstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// SchemaVersion is the version of the on-disk layout written by this version of the store
+//
+// Versions:
+//  1. Transaction records hold their containing blocks
+//  2. Containing blocks are stored separately from transaction records
+//  3. Transaction records may be compressed
+//  4. Transactions are indexed by height
+//  5. Block times are indexed by timestamp
+//  6. Every transaction has an index record
+const SchemaVersion uint64 = 6
+
+var (
+	// ErrSchemaTooNew occurs when the database was written by a newer version of the store
+	ErrSchemaTooNew = errors.New("database schema is newer than supported")
+
+	// ErrMalformedSchemaVersion occurs when the stored schema version cannot be read
+	ErrMalformedSchemaVersion = errors.New("malformed schema version")
+)
+
+// Names of the metadata records
+const (
+	// schemaVersionName holds the schema version of the database
+	schemaVersionName = "schema_version"
+
+	// migrationProgressName holds the version being migrated to and the last migrated key
+	migrationProgressName = "migration_progress"
+)
+
+// migrationCheckpointInterval is the number of keys migrated between saving progress
+const migrationCheckpointInterval = 1000
+
+// migration upgrades the database from the previous schema version to version.
+// Migrations visit the keys in order and must be safe to run again on keys they
+// have already migrated, so that an interrupted migration can be resumed from
+// its last checkpoint.
+type migration struct {
+	version     uint64
+	description string
+
+	// migrate migrates a single record, nil if no existing record needs to change
+	migrate func(handler *TransactionStore, key []byte, value []byte) error
+}
+
+// migrations lists every migration in the order they are applied
+var migrations = []migration{
+	{
+		version:     2,
+		description: "move containing blocks out of transaction records",
+		migrate:     (*TransactionStore).migrateContainingBlocks,
+	},
+	{
+		// Uncompressed records are still read, but older versions cannot read compressed ones
+		version:     3,
+		description: "allow compressed transaction records",
+	},
+	{
+		version:     4,
+		description: "index transactions by height",
+		migrate:     (*TransactionStore).migrateHeightIndex,
+	},
+	{
+		version:     5,
+		description: "index block times by timestamp",
+		migrate:     (*TransactionStore).migrateBlockTimes,
+	},
+	{
+		version:     6,
+		description: "index transactions stored before they were indexed",
+		migrate:     (*TransactionStore).migrateIndexes,
+	},
+}
+
+// MigrationHandler is called when a migration to the given schema version begins
+type MigrationHandler func(version uint64, description string)
+
+// GetSchemaVersion returns the schema version of the database. A database with no
+// version is either empty, and has the current version, or was written before
+// versions were recorded.
+func (handler *TransactionStore) GetSchemaVersion() (uint64, error) {
+	value, err := handler.backend.Get(metadataKey(schemaVersionName))
+	if err != nil {
+		return 0, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	if len(value) > 0 {
+		if len(value) != 8 {
+			return 0, fmt.Errorf("%w, expected 8 bytes but was %v", ErrMalformedSchemaVersion, len(value))
+		}
+		return binary.BigEndian.Uint64(value), nil
+	}
+
+	empty, err := handler.isEmpty()
+	if err != nil {
+		return 0, err
+	}
+	if empty {
+		return SchemaVersion, nil
+	}
+
+	return 1, nil
+}
+
+// Migrate upgrades the database to the current schema version, calling onMigration, if not
+// nil, as each migration begins. A migration that was interrupted resumes where it stopped.
+// Returns ErrSchemaTooNew if the database was written by a newer version of the store.
+func (handler *TransactionStore) Migrate(onMigration MigrationHandler) error {
+	version, err := handler.GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	if version > SchemaVersion {
+		return fmt.Errorf("%w, database is at version %v but at most %v is supported", ErrSchemaTooNew, version, SchemaVersion)
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+
+		if onMigration != nil {
+			onMigration(m.version, m.description)
+		}
+		if err := handler.runMigration(&m); err != nil {
+			return fmt.Errorf("migration to version %v failed, %w", m.version, err)
+		}
+	}
+
+	return handler.putSchemaVersion(SchemaVersion)
+}
+
+// runMigration applies a migration to every key, beginning after the last checkpoint
+func (handler *TransactionStore) runMigration(m *migration) error {
+	if m.migrate == nil {
+		return handler.putSchemaVersion(m.version)
+	}
+
+	start, err := handler.getMigrationProgress(m.version)
+	if err != nil {
+		return err
+	}
+
+	progressKey := metadataKey(migrationProgressName)
+	visited := 0
+	var callbackErr error
+
+	err = handler.backend.IterateFrom(nil, start, false, func(key []byte, value []byte) error {
+		// The checkpoint is the last key migrated, which is visited again on resume
+		if bytes.Equal(key, progressKey) {
+			return nil
+		}
+
+		if callbackErr = m.migrate(handler, key, value); callbackErr != nil {
+			return callbackErr
+		}
+
+		if visited++; visited%migrationCheckpointInterval == 0 {
+			callbackErr = handler.putMigrationProgress(m.version, key)
+		}
+		return callbackErr
+	})
+	if callbackErr != nil {
+		return callbackErr
+	}
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	if err := handler.putSchemaVersion(m.version); err != nil {
+		return err
+	}
+
+	if err := handler.backend.Delete(progressKey); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// getMigrationProgress returns the last key checkpointed by the migration to the given
+// version, or nil if it has not begun
+func (handler *TransactionStore) getMigrationProgress(version uint64) ([]byte, error) {
+	value, err := handler.backend.Get(metadataKey(migrationProgressName))
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	// Progress of another migration is stale
+	if len(value) < 8 || binary.BigEndian.Uint64(value) != version {
+		return nil, nil
+	}
+
+	return value[8:], nil
+}
+
+// putMigrationProgress checkpoints the last key migrated to the given version
+func (handler *TransactionStore) putMigrationProgress(version uint64, key []byte) error {
+	value := append(heightBytes(version), key...)
+	if err := handler.backend.Put(metadataKey(migrationProgressName), value); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// putSchemaVersion records the schema version of the database
+func (handler *TransactionStore) putSchemaVersion(version uint64) error {
+	if err := handler.backend.Put(metadataKey(schemaVersionName), heightBytes(version)); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// errStopIteration stops an iteration that has found what it was looking for
+var errStopIteration = errors.New("stop iteration")
+
+// isEmpty returns true if the database holds no records other than metadata
+func (handler *TransactionStore) isEmpty() (bool, error) {
+	empty := true
+	err := handler.backend.Iterate(nil, func(key []byte, value []byte) error {
+		if bytes.HasPrefix(key, metadataKey("")) {
+			return nil
+		}
+		empty = false
+		return errStopIteration
+	})
+	if err != nil && !errors.Is(err, errStopIteration) {
+		return false, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return empty, nil
+}
@@ -0,0 +1,131 @@
+package trxstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"google.golang.org/protobuf/proto"
+)
+
+func checkSchemaVersion(t *testing.T, store *TransactionStore, expected uint64) {
+	version, err := store.GetSchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != expected {
+		t.Fatalf("Expected schema version %v, got %v", expected, version)
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		// An empty database has the current version
+		checkSchemaVersion(t, store, SchemaVersion)
+
+		migrated := 0
+		if err := store.Migrate(func(version uint64, description string) { migrated++ }); err != nil {
+			t.Fatal(err)
+		}
+		if migrated != 0 {
+			t.Fatalf("Expected no migrations, got %v", migrated)
+		}
+
+		// The version is recorded, so the database is not mistaken for an unversioned one
+		if err := store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{1}}, &koinos.BlockTopology{Id: []byte{1}}); err != nil {
+			t.Fatal(err)
+		}
+		checkSchemaVersion(t, store, SchemaVersion)
+
+		// A database is migrated without a handler
+		if err := b.Put(metadataKey(schemaVersionName), heightBytes(SchemaVersion-1)); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Migrate(nil); err != nil {
+			t.Fatal(err)
+		}
+		checkSchemaVersion(t, store, SchemaVersion)
+
+		// A database written by a newer version is refused
+		if err := b.Put(metadataKey(schemaVersionName), heightBytes(SchemaVersion+1)); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Migrate(func(version uint64, description string) {
+			t.Error("Unexpected migration to version ", version)
+		}); !errors.Is(err, ErrSchemaTooNew) {
+			t.Fatalf("Expected %v, got %v", ErrSchemaTooNew, err)
+		}
+
+		if err := b.Put(metadataKey(schemaVersionName), []byte{2}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.GetSchemaVersion(); !errors.Is(err, ErrMalformedSchemaVersion) {
+			t.Fatalf("Expected %v, got %v", ErrMalformedSchemaVersion, err)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestResumeMigration(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		// An unversioned database with records in the legacy layout
+		for id := byte(1); id <= 4; id++ {
+			record := &transaction_store.TransactionItem{
+				Transaction:      &protocol.Transaction{Id: []byte{id}},
+				ContainingBlocks: [][]byte{{id}},
+			}
+			recordBytes, err := proto.Marshal(record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Put(record.Transaction.Id, recordBytes); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// A migration to version 2 was interrupted after migrating the first two records
+		if err := store.putMigrationProgress(2, []byte{2}); err != nil {
+			t.Fatal(err)
+		}
+		checkSchemaVersion(t, store, 1)
+
+		migrated := make([]uint64, 0)
+		if err := store.Migrate(func(version uint64, description string) { migrated = append(migrated, version) }); err != nil {
+			t.Fatal(err)
+		}
+		if len(migrated) != int(SchemaVersion-1) || migrated[0] != 2 {
+			t.Fatalf("Expected migrations from version 2, got %v", migrated)
+		}
+		checkSchemaVersion(t, store, SchemaVersion)
+
+		// Records before the checkpoint were not visited again
+		for id := byte(1); id <= 4; id++ {
+			record, err := store.getRecord([]byte{id})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if legacy := len(record.ContainingBlocks) > 0; legacy != (id < 2) {
+				t.Fatalf("Unexpected record 0x%x after resuming, %v", id, record)
+			}
+		}
+
+		progress, err := b.Get(metadataKey(migrationProgressName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(progress) != 0 {
+			t.Fatalf("Expected migration progress to be removed, got %v", progress)
+		}
+
+		CloseBackend(b)
+	}
+}
@@ -0,0 +1,52 @@
+package trxstore
+
+import (
+	"bytes"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/multiformats/go-multihash"
+)
+
+// recoverSigners returns the addresses of the keys that produced the
+// transaction's signatures. Signatures that are not recoverable secp256k1
+// signatures of the transaction ID are skipped, since authority may be
+// delegated to contracts that accept other kinds of signatures.
+func recoverSigners(tx *protocol.Transaction) [][]byte {
+	id, err := multihash.Decode(tx.Id)
+	if err != nil {
+		return nil
+	}
+
+	signers := make([][]byte, 0, len(tx.Signatures))
+
+	for _, signature := range tx.Signatures {
+		publicKey, _, err := btcec.RecoverCompact(btcec.S256(), signature, id.Digest)
+		if err != nil {
+			continue
+		}
+
+		address, err := btcutil.NewAddressPubKey(publicKey.SerializeCompressed(), &chaincfg.MainNetParams)
+		if err != nil {
+			continue
+		}
+
+		signer := base58.Decode(address.EncodeAddress())
+
+		duplicate := false
+		for _, s := range signers {
+			if bytes.Equal(s, signer) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			signers = append(signers, signer)
+		}
+	}
+
+	return signers
+}
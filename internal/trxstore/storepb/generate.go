@@ -0,0 +1,7 @@
+// Package storepb holds the messages of records.proto, the encoding of the
+// records the transaction store keeps besides transactions, generated with
+// protoc-gen-go. KOINOS_PROTO must name a checkout of koinos-proto, which the
+// koinos imports are resolved from.
+package storepb
+
+//go:generate protoc -I . -I $KOINOS_PROTO --go_out=. --go_opt=paths=source_relative records.proto
@@ -9,8 +9,7 @@ import (
 	"github.com/koinos/koinos-proto-golang/v2/koinos"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
-
-	"google.golang.org/protobuf/proto"
+	util "github.com/koinos/koinos-util-golang/v2"
 )
 
 var (
@@ -22,95 +21,422 @@ var (
 
 	// ErrBackend occurs when there is an error in the backend
 	ErrBackend = errors.New("error in backend")
+
+	// ErrTransactionIDMismatch occurs when a transaction ID is not the hash of its header
+	ErrTransactionIDMismatch = errors.New("transaction id does not match header")
+
+	// ErrReceiptMismatch occurs when a receipt does not belong to the transaction it is added with
+	ErrReceiptMismatch = errors.New("receipt does not match transaction")
+
+	// ErrInvalidTransactionID occurs when a transaction ID is empty or collides with the keys of auxiliary records
+	ErrInvalidTransactionID = errors.New("invalid transaction id")
+
+	// ErrBlockResolution occurs when the blocks including a transaction cannot be looked up
+	ErrBlockResolution = errors.New("error resolving blocks")
 )
 
-// TransactionStore contains a backend object and handles requests
+// TransactionStore contains a backend object and handles requests.
+//
+// Reads take no locks and rely on the backend's per-key atomicity. Records are
+// written in an order that keeps partially added transactions consistent: an
+// index entry may refer to a transaction that is not visible yet, but never the
+// reverse. Writers of the same record are serialized by key-striped mutexes.
+//
+// A store staging a batch is a copy of the store with another backend, so the
+// state shared between writers is held by pointers.
 type TransactionStore struct {
 	backend TransactionStoreBackend
-	rwmutex sync.RWMutex
+
+	// itemLocks serializes writers of the same transaction item
+	itemLocks *stripedMutex
+
+	// usageLocks serializes writers of the same resource usage bucket, they are locked after item locks
+	usageLocks *stripedMutex
+
+	// outbox assigns the IDs of outbox entries
+	outbox *outboxSequence
+
+	verifyTransactionIDs bool
+
+	// compression is applied to the transaction records written by the store
+	compression Compression
+
+	// everyBlockTime records the time of every block rather than only those beginning a usage bucket
+	everyBlockTime bool
+
+	// readParallelism is the number of goroutines items read together are decoded by
+	readParallelism int
+
+	// cache holds recently read items, nil if caching is disabled
+	cache *itemCache
+
+	// resolveBlocks looks up the blocks including the transactions indexed by migration, nil if they cannot be
+	resolveBlocks BlockResolver
+}
+
+// Option configures optional TransactionStore behavior
+type Option func(*TransactionStore)
+
+// WithTransactionIDVerification rejects included transactions whose ID is not the hash of their header
+func WithTransactionIDVerification(enabled bool) Option {
+	return func(handler *TransactionStore) {
+		handler.verifyTransactionIDs = enabled
+	}
+}
+
+// WithCacheBytes caches decoded transaction items up to the given total encoded size in bytes,
+// a size of 0 disables the cache
+func WithCacheBytes(bytes int) Option {
+	return func(handler *TransactionStore) {
+		if bytes > 0 {
+			handler.cache = newItemCache(bytes)
+		} else {
+			handler.cache = nil
+		}
+	}
+}
+
+// WithCompression compresses the transaction records written by the store. Records
+// written with any compression, or none, can always be read.
+func WithCompression(compression Compression) Option {
+	return func(handler *TransactionStore) {
+		handler.compression = compression
+	}
+}
+
+// WithEveryBlockTime records the time of every block, which retention by age needs, rather than only
+// those of the blocks beginning a resource usage bucket, which resource usage is queried by time with
+func WithEveryBlockTime(enabled bool) Option {
+	return func(handler *TransactionStore) {
+		handler.everyBlockTime = enabled
+	}
+}
+
+// WithReadParallelism decodes the items read by a single GetTransactionsByID call
+// using up to the given number of goroutines
+func WithReadParallelism(parallelism int) Option {
+	return func(handler *TransactionStore) {
+		handler.readParallelism = parallelism
+	}
+}
+
+// WithBlockResolver looks up the blocks including transactions that were stored before they were indexed,
+// so that the migration indexing them indexes them at the heights of those blocks, with their receipts
+func WithBlockResolver(resolveBlocks BlockResolver) Option {
+	return func(handler *TransactionStore) {
+		handler.resolveBlocks = resolveBlocks
+	}
 }
 
 // NewTransactionStore creates a new TransactionStore wrapping the provided backend
-func NewTransactionStore(backend TransactionStoreBackend) *TransactionStore {
-	return &TransactionStore{backend: backend}
+func NewTransactionStore(backend TransactionStoreBackend, opts ...Option) *TransactionStore {
+	handler := &TransactionStore{
+		backend:    backend,
+		itemLocks:  &stripedMutex{},
+		usageLocks: &stripedMutex{},
+		outbox:     &outboxSequence{},
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+
+	return handler
 }
 
 // AddIncludedTransaction adds a transaction to with the associated block topology
 func (handler *TransactionStore) AddIncludedTransaction(tx *protocol.Transaction, topology *koinos.BlockTopology) error {
-	handler.rwmutex.Lock()
-	defer handler.rwmutex.Unlock()
+	return handler.AddIncludedTransactionWithReceipt(tx, nil, topology)
+}
+
+// AddIncludedTransactionWithReceipt adds a transaction with the associated block topology and the
+// receipt of its execution in that block. The receipt, which may be nil, is only stored and indexed
+// when the transaction is included for the first time.
+func (handler *TransactionStore) AddIncludedTransactionWithReceipt(tx *protocol.Transaction, receipt *protocol.TransactionReceipt, topology *koinos.BlockTopology) error {
+	if err := handler.checkIncludedTransaction(tx, receipt); err != nil {
+		return err
+	}
+
+	defer handler.itemLocks.lock(tx.Id).Unlock()
+	if receipt != nil {
+		defer handler.usageLocks.lock(usageBucketKey(receipt, topology.Height)).Unlock()
+	}
 
-	itemBytes, err := handler.backend.Get(tx.Id)
+	return handler.addIncludedTransaction(tx, receipt, topology)
+}
+
+// checkIncludedTransaction returns an error if a transaction or its receipt may not be added
+func (handler *TransactionStore) checkIncludedTransaction(tx *protocol.Transaction, receipt *protocol.TransactionReceipt) error {
+	if err := checkTransactionIDKey(tx.Id); err != nil {
+		return err
+	}
+
+	if receipt != nil && !bytes.Equal(receipt.Id, tx.Id) {
+		return fmt.Errorf("%w, receipt of 0x%x does not belong to 0x%x", ErrReceiptMismatch, receipt.Id, tx.Id)
+	}
+
+	if handler.verifyTransactionIDs {
+		return checkTransactionID(tx)
+	}
+
+	return nil
+}
+
+// addIncludedTransaction adds a checked transaction, the locks of its item and usage bucket must be held
+func (handler *TransactionStore) addIncludedTransaction(tx *protocol.Transaction, receipt *protocol.TransactionReceipt, topology *koinos.BlockTopology) error {
+	record, err := handler.getRecord(tx.Id)
 	if err != nil {
-		return fmt.Errorf("%w, %v", ErrBackend, err)
+		return err
 	}
 
-	if len(itemBytes) == 0 {
-		item := transaction_store.TransactionItem{
-			Transaction:      tx,
-			ContainingBlocks: [][]byte{topology.Id},
+	if record != nil {
+		if err := handler.retainUntil(tx.Id, topology.Height); err != nil {
+			return err
 		}
+		return handler.addContainingBlocks(record, [][]byte{topology.Id})
+	}
 
-		itemBytes, err = proto.Marshal(&item)
-		if err != nil {
-			return fmt.Errorf("%w, %v", ErrSerialization, err)
-		}
+	record = &transaction_store.TransactionItem{Transaction: tx}
 
-		err = handler.backend.Put(tx.Id, itemBytes)
-		if err != nil {
-			return fmt.Errorf("%w, %v", ErrBackend, err)
+	// The record is stored last so that an interrupted write is retried in full, the usage is
+	// counted before the receipt is stored so that it is not counted again
+	if receipt != nil {
+		if err := handler.addUsage(receipt, topology.Height); err != nil {
+			return err
 		}
-	} else {
-		item := &transaction_store.TransactionItem{}
-		if err := proto.Unmarshal(itemBytes, item); err != nil {
-			return fmt.Errorf("%w, %v", ErrDeserialization, err)
+		if err := handler.putReceipt(receipt); err != nil {
+			return err
 		}
+	}
 
-		for _, blockID := range item.ContainingBlocks {
-			if bytes.Equal(blockID, topology.Id) {
-				return nil
-			}
-		}
+	if err := handler.addIndexes(tx, receipt, topology.Height); err != nil {
+		return err
+	}
 
-		item.ContainingBlocks = append(item.ContainingBlocks, topology.Id)
-		itemBytes, err = proto.Marshal(item)
-		if err != nil {
-			return fmt.Errorf("%w, %v", ErrSerialization, err)
-		}
+	if err := handler.addContainingBlocks(record, [][]byte{topology.Id}); err != nil {
+		return err
+	}
 
-		err = handler.backend.Put(tx.Id, itemBytes)
-		if err != nil {
-			return fmt.Errorf("%w, %v", ErrBackend, err)
-		}
+	return handler.putItem(record)
+}
+
+// checkTransactionIDKey returns an error if a transaction ID cannot be used as the key of a transaction record
+func checkTransactionIDKey(id []byte) error {
+	if len(id) == 0 {
+		return fmt.Errorf("%w, transaction id is empty", ErrInvalidTransactionID)
+	}
+
+	if isAuxiliaryKey(id) {
+		return fmt.Errorf("%w, 0x%x begins with the auxiliary record prefix", ErrInvalidTransactionID, id)
+	}
+
+	return nil
+}
+
+// checkTransactionID returns an error if the transaction ID is not the multihash of its header
+func checkTransactionID(tx *protocol.Transaction) error {
+	if tx.Header == nil {
+		return fmt.Errorf("%w, transaction has no header", ErrTransactionIDMismatch)
+	}
+
+	id, err := util.HashMessage(tx.Header)
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrSerialization, err)
+	}
+
+	if !bytes.Equal(id, tx.Id) {
+		return fmt.Errorf("%w, expected 0x%x but was 0x%x", ErrTransactionIDMismatch, id, tx.Id)
+	}
+
+	return nil
+}
+
+// putItem stores the transaction record of an item under its transaction ID
+func (handler *TransactionStore) putItem(item *transaction_store.TransactionItem) error {
+	itemBytes, err := handler.marshalItem(item)
+	if err != nil {
+		return err
+	}
+
+	err = handler.backend.Put(item.Transaction.Id, itemBytes)
+	if handler.cache != nil {
+		handler.cache.remove(item.Transaction.Id)
+	}
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
 	}
 
 	return nil
 }
 
-// GetTransactionsByID returns transactions by transaction ID
+// GetTransactionsByID returns transactions by transaction ID, omitting those that are not stored.
+// The items that are not cached are read from one backend snapshot. The returned items may be
+// shared with the cache and must not be modified.
 func (handler *TransactionStore) GetTransactionsByID(trxIDs [][]byte) ([]*transaction_store.TransactionItem, error) {
-	trxs := make([]*transaction_store.TransactionItem, 0)
+	items := make([]*transaction_store.TransactionItem, len(trxIDs))
+	var generations []uint64
+	var missing []int
+	var missingIDs [][]byte
 
-	handler.rwmutex.RLock()
-	defer handler.rwmutex.RUnlock()
+	for i, tid := range trxIDs {
+		if err := checkTransactionIDKey(tid); err != nil {
+			return nil, err
+		}
 
-	for _, tid := range trxIDs {
-		if tid == nil {
-			return nil, errors.New("transaction id was nil")
+		var generation uint64
+		if handler.cache != nil {
+			if items[i], generation = handler.cache.get(tid); items[i] != nil {
+				continue
+			}
 		}
-		itemBytes, err := handler.backend.Get(tid)
+
+		generations = append(generations, generation)
+		missing = append(missing, i)
+		missingIDs = append(missingIDs, tid)
+	}
+
+	if len(missing) > 0 {
+		// The containing blocks are read from the same snapshot as the records
+		prefixes := make([][]byte, len(missingIDs))
+		for i, trxID := range missingIDs {
+			prefixes[i] = containingBlockPrefix(trxID)
+		}
+		blocks := make([][][]byte, len(missingIDs))
+		values, err := handler.backend.GetManyWithPrefixes(missingIDs, prefixes, func(i int, key []byte, value []byte) error {
+			blocks[i] = append(blocks[i], value)
+			return nil
+		})
 		if err != nil {
 			return nil, fmt.Errorf("%w, %v", ErrBackend, err)
 		}
-		if len(itemBytes) != 0 {
-			item := &transaction_store.TransactionItem{}
-			if err := proto.Unmarshal(itemBytes, item); err != nil {
-				return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+
+		decoded, err := handler.decodeItems(values, blocks)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, i := range missing {
+			items[i] = decoded[j]
+			if decoded[j] != nil && handler.cache != nil {
+				handler.cache.add(trxIDs[i], decoded[j], generations[j])
 			}
+		}
+	}
 
+	trxs := make([]*transaction_store.TransactionItem, 0, len(items))
+	for _, item := range items {
+		if item != nil {
 			trxs = append(trxs, item)
 		}
 	}
 
 	return trxs, nil
 }
+
+// decodeItems unmarshals stored items, nil for empty values, and adds the containing blocks stored
+// separately from each. Values are split among up to readParallelism goroutines.
+func (handler *TransactionStore) decodeItems(values [][]byte, blocks [][][]byte) ([]*transaction_store.TransactionItem, error) {
+	items := make([]*transaction_store.TransactionItem, len(values))
+
+	decode := func(start int, end int) error {
+		for i := start; i < end; i++ {
+			if len(values[i]) == 0 {
+				continue
+			}
+			items[i] = &transaction_store.TransactionItem{}
+			if err := unmarshalItem(values[i], items[i]); err != nil {
+				return err
+			}
+			mergeContainingBlocks(items[i], blocks[i])
+		}
+		return nil
+	}
+
+	workers := handler.readParallelism
+	if workers > len(values) {
+		workers = len(values)
+	}
+	if workers <= 1 {
+		return items, decode(0, len(values))
+	}
+
+	errs := make([]error, workers)
+	chunk := (len(values) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start := w * chunk
+		end := start + chunk
+		if end > len(values) {
+			end = len(values)
+		}
+
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			errs[w] = decode(start, end)
+		}(w)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return items, nil
+}
+
+// getItem returns the transaction item with the given ID, or nil if it is not stored.
+// Items may be shared with the cache and must not be modified.
+func (handler *TransactionStore) getItem(trxID []byte) (*transaction_store.TransactionItem, error) {
+	var generation uint64
+	if handler.cache != nil {
+		var item *transaction_store.TransactionItem
+		if item, generation = handler.cache.get(trxID); item != nil {
+			return item, nil
+		}
+	}
+
+	item, err := handler.getRecord(trxID)
+	if err != nil || item == nil {
+		return nil, err
+	}
+
+	if err := handler.assembleItem(item); err != nil {
+		return nil, err
+	}
+
+	if handler.cache != nil {
+		handler.cache.add(trxID, item, generation)
+	}
+
+	return item, nil
+}
+
+// getRecord returns the transaction record with the given ID as it is stored, without the
+// containing blocks stored separately, or nil if it is not stored
+func (handler *TransactionStore) getRecord(trxID []byte) (*transaction_store.TransactionItem, error) {
+	itemBytes, err := handler.backend.Get(trxID)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+	if len(itemBytes) == 0 {
+		return nil, nil
+	}
+
+	item := &transaction_store.TransactionItem{}
+	if err := unmarshalItem(itemBytes, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// CacheStats returns the statistics of the transaction item cache, which are zero if it is disabled
+func (handler *TransactionStore) CacheStats() CacheStats {
+	if handler.cache == nil {
+		return CacheStats{}
+	}
+
+	return handler.cache.statistics()
+}
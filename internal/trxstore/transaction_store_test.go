@@ -3,21 +3,28 @@ package trxstore
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"os"
+	"runtime"
 	"testing"
 
 	"github.com/dgraph-io/badger/v3"
 
 	"github.com/koinos/koinos-proto-golang/v2/koinos"
 	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	util "github.com/koinos/koinos-util-golang/v2"
 )
 
 const (
-	MapBackendType    = 0
-	BadgerBackendType = 1
+	MapBackendType       = 0
+	BadgerBackendType    = 1
+	EncryptedBackendType = 2
 )
 
-var backendTypes = [...]int{MapBackendType, BadgerBackendType}
+var backendTypes = [...]int{MapBackendType, BadgerBackendType, EncryptedBackendType}
+
+// testEncryptionKey is the AES-128 key of encrypted test backends
+var testEncryptionKey = []byte("0123456789abcdef")
 
 func NewBackend(backendType int) TransactionStoreBackend {
 	var backend TransactionStoreBackend
@@ -31,6 +38,12 @@ func NewBackend(backendType int) TransactionStoreBackend {
 		}
 		opts := badger.DefaultOptions(dirname)
 		backend = NewBadgerBackend(opts)
+	case EncryptedBackendType:
+		encrypted, err := NewEncryptedBackend(NewMapBackend(), testEncryptionKey)
+		if err != nil {
+			panic(err)
+		}
+		backend = encrypted
 	default:
 		panic("unknown backend type")
 	}
@@ -43,11 +56,29 @@ func CloseBackend(b interface{}) {
 		break
 	case *BadgerBackend:
 		t.Close()
+	case *EncryptedBackend:
+		CloseBackend(t.backend)
 	default:
 		panic("unknown backend type")
 	}
 }
 
+// nopWriteBatch discards its writes, failing to flush if err is set
+type nopWriteBatch struct {
+	err error
+}
+
+func (wb *nopWriteBatch) Put(key []byte, value []byte) error {
+	return nil
+}
+
+func (wb *nopWriteBatch) Flush() error {
+	return wb.err
+}
+
+func (wb *nopWriteBatch) Cancel() {
+}
+
 type ErrorBackend struct {
 }
 
@@ -60,11 +91,41 @@ func (backend *ErrorBackend) Put(key []byte, value []byte) error {
 	return errors.New("Error on put")
 }
 
+// Delete returns an error
+func (backend *ErrorBackend) Delete(key []byte) error {
+	return errors.New("Error on delete")
+}
+
 // Get gets an error
 func (backend *ErrorBackend) Get(key []byte) ([]byte, error) {
 	return nil, errors.New("Error on get")
 }
 
+// GetMany returns an error
+func (backend *ErrorBackend) GetMany(keys [][]byte) ([][]byte, error) {
+	return nil, errors.New("Error on get")
+}
+
+// GetManyWithPrefixes returns an error
+func (backend *ErrorBackend) GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error) {
+	return nil, errors.New("Error on get")
+}
+
+// NewWriteBatch returns a batch that fails to flush
+func (backend *ErrorBackend) NewWriteBatch() WriteBatch {
+	return &nopWriteBatch{err: errors.New("Error on flush")}
+}
+
+// Iterate returns an error
+func (backend *ErrorBackend) Iterate(prefix []byte, f func(key []byte, value []byte) error) error {
+	return errors.New("Error on iterate")
+}
+
+// IterateFrom returns an error
+func (backend *ErrorBackend) IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error {
+	return errors.New("Error on iterate")
+}
+
 type BadBackend struct {
 }
 
@@ -77,11 +138,45 @@ func (backend *BadBackend) Put(key []byte, value []byte) error {
 	return nil
 }
 
+// Delete does nothing
+func (backend *BadBackend) Delete(key []byte) error {
+	return nil
+}
+
 // Get gets an error
 func (backend *BadBackend) Get(key []byte) ([]byte, error) {
 	return []byte{0, 0, 255, 255, 255, 255, 255}, nil
 }
 
+// GetMany gets a bad value for every key
+func (backend *BadBackend) GetMany(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i := range keys {
+		values[i], _ = backend.Get(keys[i])
+	}
+	return values, nil
+}
+
+// GetManyWithPrefixes gets a bad value for every key and visits nothing
+func (backend *BadBackend) GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error) {
+	return backend.GetMany(keys)
+}
+
+// NewWriteBatch returns a batch that does nothing
+func (backend *BadBackend) NewWriteBatch() WriteBatch {
+	return &nopWriteBatch{}
+}
+
+// Iterate visits a single bad record
+func (backend *BadBackend) Iterate(prefix []byte, f func(key []byte, value []byte) error) error {
+	return f([]byte{1}, []byte{0, 0, 255, 255, 255, 255, 255})
+}
+
+// IterateFrom visits a single bad record
+func (backend *BadBackend) IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error {
+	return backend.Iterate(prefix, f)
+}
+
 type LongBackend struct {
 }
 
@@ -94,11 +189,45 @@ func (backend *LongBackend) Put(key []byte, value []byte) error {
 	return nil
 }
 
+// Delete does nothing
+func (backend *LongBackend) Delete(key []byte) error {
+	return nil
+}
+
 // Get gets an error
 func (backend *LongBackend) Get(key []byte) ([]byte, error) {
 	return []byte{2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, nil
 }
 
+// GetMany gets a long value for every key
+func (backend *LongBackend) GetMany(keys [][]byte) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i := range keys {
+		values[i], _ = backend.Get(keys[i])
+	}
+	return values, nil
+}
+
+// GetManyWithPrefixes gets a long value for every key and visits nothing
+func (backend *LongBackend) GetManyWithPrefixes(keys [][]byte, prefixes [][]byte, f func(prefix int, key []byte, value []byte) error) ([][]byte, error) {
+	return backend.GetMany(keys)
+}
+
+// NewWriteBatch returns a batch that does nothing
+func (backend *LongBackend) NewWriteBatch() WriteBatch {
+	return &nopWriteBatch{}
+}
+
+// Iterate visits nothing
+func (backend *LongBackend) Iterate(prefix []byte, f func(key []byte, value []byte) error) error {
+	return nil
+}
+
+// IterateFrom visits nothing
+func (backend *LongBackend) IterateFrom(prefix []byte, start []byte, reverse bool, f func(key []byte, value []byte) error) error {
+	return nil
+}
+
 func TestAddTransaction(t *testing.T) {
 	// Add the transactions
 	for bType := range backendTypes {
@@ -218,3 +347,168 @@ func TestAddTransaction(t *testing.T) {
 		}
 	}
 }
+
+func TestTransactionIDVerification(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b, WithTransactionIDVerification(true))
+		topology := &koinos.BlockTopology{Id: []byte{1}}
+
+		trx := &protocol.Transaction{Header: &protocol.TransactionHeader{RcLimit: 10, Nonce: []byte{1}}}
+		id, err := util.HashMessage(trx.Header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trx.Id = id
+
+		if err = store.AddIncludedTransaction(trx, topology); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+
+		// A transaction claiming the ID of another must be rejected
+		forged := &protocol.Transaction{Id: id, Header: &protocol.TransactionHeader{RcLimit: 20, Nonce: []byte{1}}}
+		err = store.AddIncludedTransaction(forged, &koinos.BlockTopology{Id: []byte{2}})
+		if !errors.Is(err, ErrTransactionIDMismatch) {
+			t.Fatal("Got unexpected error adding forged transaction: ", err)
+		}
+
+		err = store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{2}}, topology)
+		if !errors.Is(err, ErrTransactionIDMismatch) {
+			t.Fatal("Got unexpected error adding transaction without header: ", err)
+		}
+
+		trxs, err := store.GetTransactionsByID([][]byte{id, {2}})
+		if err != nil {
+			t.Fatal("Error getting transaction: ", err)
+		}
+		if len(trxs) != 1 {
+			t.Fatal("Incorrect number of transactions returned")
+		}
+		if trxs[0].Transaction.Header.RcLimit != 10 || len(trxs[0].ContainingBlocks) != 1 {
+			t.Fatal("Stored transaction was modified by a forged transaction")
+		}
+
+		// Without verification the mismatching transaction is accepted
+		store = NewTransactionStore(b)
+		if err = store.AddIncludedTransaction(forged, &koinos.BlockTopology{Id: []byte{2}}); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestAuxiliaryTransactionIDs(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+		if err := store.Migrate(nil); err != nil {
+			t.Fatal(err)
+		}
+
+		// An ID colliding with the schema version record must neither overwrite it nor read it back as an item
+		trx := &protocol.Transaction{Id: metadataKey(schemaVersionName), Header: &protocol.TransactionHeader{RcLimit: 10}}
+		topology := &koinos.BlockTopology{Id: []byte{1}}
+		if err := store.AddIncludedTransaction(trx, topology); !errors.Is(err, ErrInvalidTransactionID) {
+			t.Fatal("Expected ErrInvalidTransactionID, got ", err)
+		}
+		if err := store.AddIncludedTransactions([]*IncludedTransaction{{Transaction: trx, Topology: topology}}); !errors.Is(err, ErrInvalidTransactionID) {
+			t.Fatal("Expected ErrInvalidTransactionID, got ", err)
+		}
+		if _, err := store.GetTransactionsByID([][]byte{trx.Id}); !errors.Is(err, ErrInvalidTransactionID) {
+			t.Fatal("Expected ErrInvalidTransactionID, got ", err)
+		}
+		if _, err := store.GetTransactionsByID([][]byte{{}}); !errors.Is(err, ErrInvalidTransactionID) {
+			t.Fatal("Expected ErrInvalidTransactionID, got ", err)
+		}
+
+		version, err := store.GetSchemaVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version != SchemaVersion {
+			t.Fatalf("Expected schema version %v, got %v", SchemaVersion, version)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestGetTransactionsByIDBatch(t *testing.T) {
+	for bType := range backendTypes {
+		for _, parallelism := range []int{0, 3} {
+			b := NewBackend(bType)
+			store := NewTransactionStore(b, WithReadParallelism(parallelism), WithCacheBytes(1<<20))
+
+			for id := byte(1); id <= 20; id += 2 {
+				if err := store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{id}}, &koinos.BlockTopology{Id: []byte{1}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Cache some of the requested items so that cached and read items are interleaved
+			if _, err := store.GetTransactionsByID([][]byte{{5}, {11}}); err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([][]byte, 0)
+			for id := byte(20); id > 0; id-- {
+				ids = append(ids, []byte{id})
+			}
+			trxs, err := store.GetTransactionsByID(ids)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Items are returned in request order, omitting those that are not stored
+			if len(trxs) != 10 {
+				t.Fatalf("Expected 10 transactions, got %v", len(trxs))
+			}
+			for i, trx := range trxs {
+				if !bytes.Equal(trx.Transaction.Id, []byte{byte(19 - 2*i)}) {
+					t.Fatalf("Expected transaction %v at %v, got %v", 19-2*i, i, trx.Transaction.Id)
+				}
+			}
+
+			CloseBackend(b)
+		}
+	}
+
+	store := NewTransactionStore(&BadBackend{}, WithReadParallelism(2))
+	if _, err := store.GetTransactionsByID([][]byte{{1}, {2}, {3}}); !errors.Is(err, ErrDeserialization) {
+		t.Fatal("Expected ErrDeserialization, got ", err)
+	}
+}
+
+// BenchmarkGetTransactionsByID reads a full block's worth of transactions per call
+func BenchmarkGetTransactionsByID(b *testing.B) {
+	const blockSize = 500
+
+	backend := NewBackend(BadgerBackendType)
+	defer CloseBackend(backend)
+
+	ids := make([][]byte, blockSize)
+	store := NewTransactionStore(backend)
+	for i := range ids {
+		ids[i] = []byte{0x12, byte(i >> 8), byte(i)}
+		trx := &protocol.Transaction{
+			Id:         ids[i],
+			Header:     &protocol.TransactionHeader{Payer: ids[i], RcLimit: 100000000},
+			Operations: []*protocol.Operation{{Op: &protocol.Operation_CallContract{CallContract: &protocol.CallContractOperation{ContractId: ids[i], Args: make([]byte, 256)}}}},
+		}
+		if err := store.AddIncludedTransaction(trx, &koinos.BlockTopology{Id: []byte{1}}); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, parallelism := range []int{1, runtime.NumCPU()} {
+		store := NewTransactionStore(backend, WithReadParallelism(parallelism))
+		b.Run(fmt.Sprintf("parallelism=%v", parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := store.GetTransactionsByID(ids); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package trxstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore/storepb"
+	"google.golang.org/protobuf/proto"
+)
+
+// UsageBucketSize is the number of blocks whose resource usage is aggregated together
+const UsageBucketSize = 1000
+
+// errEndOfRange stops iteration once the end of a height range has been reached
+var errEndOfRange = errors.New("end of range")
+
+// ResourceUsage is the resources used by the transactions an account paid for
+type ResourceUsage struct {
+	// Height is the first block height of the bucket the usage was aggregated in
+	Height uint64
+
+	Transactions         uint64
+	RcUsed               uint64
+	DiskStorageUsed      uint64
+	NetworkBandwidthUsed uint64
+	ComputeBandwidthUsed uint64
+}
+
+// Add adds the resources of other to the usage
+func (usage *ResourceUsage) Add(other *ResourceUsage) {
+	usage.Transactions += other.Transactions
+	usage.RcUsed += other.RcUsed
+	usage.DiskStorageUsed += other.DiskStorageUsed
+	usage.NetworkBandwidthUsed += other.NetworkBandwidthUsed
+	usage.ComputeBandwidthUsed += other.ComputeBandwidthUsed
+}
+
+// marshal encodes the usage, without its height which is part of the key, as storepb.ResourceUsage
+func (usage *ResourceUsage) marshal() []byte {
+	buf, _ := proto.Marshal(&storepb.ResourceUsage{
+		Transactions:         usage.Transactions,
+		RcUsed:               usage.RcUsed,
+		DiskStorageUsed:      usage.DiskStorageUsed,
+		NetworkBandwidthUsed: usage.NetworkBandwidthUsed,
+		ComputeBandwidthUsed: usage.ComputeBandwidthUsed,
+	})
+	return buf
+}
+
+func unmarshalResourceUsage(buf []byte) (*ResourceUsage, error) {
+	message := &storepb.ResourceUsage{}
+	if err := proto.Unmarshal(buf, message); err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrDeserialization, err)
+	}
+
+	return &ResourceUsage{
+		Transactions:         message.Transactions,
+		RcUsed:               message.RcUsed,
+		DiskStorageUsed:      message.DiskStorageUsed,
+		NetworkBandwidthUsed: message.NetworkBandwidthUsed,
+		ComputeBandwidthUsed: message.ComputeBandwidthUsed,
+	}, nil
+}
+
+// usageBucketKey returns the key of the usage bucket a receipt at the given height is added to
+func usageBucketKey(receipt *protocol.TransactionReceipt, height uint64) []byte {
+	return usageKey(receipt.Payer, height-height%UsageBucketSize)
+}
+
+// addUsage adds the resources used by a transaction to its payer's bucket at the given height, unless
+// its receipt is already stored. It is called before the receipt is stored, so that a transaction
+// whose records are written again after an interrupted write is counted once. The lock of the bucket
+// must be held.
+func (handler *TransactionStore) addUsage(receipt *protocol.TransactionReceipt, height uint64) error {
+	stored, err := handler.backend.Get(receiptKey(receipt.Id))
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+	if len(stored) > 0 {
+		return nil
+	}
+
+	key := usageBucketKey(receipt, height)
+	usage := &ResourceUsage{}
+	usageBytes, err := handler.backend.Get(key)
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+	if len(usageBytes) > 0 {
+		if usage, err = unmarshalResourceUsage(usageBytes); err != nil {
+			return err
+		}
+	}
+
+	usage.Add(&ResourceUsage{
+		Transactions:         1,
+		RcUsed:               receipt.RcUsed,
+		DiskStorageUsed:      receipt.DiskStorageUsed,
+		NetworkBandwidthUsed: receipt.NetworkBandwidthUsed,
+		ComputeBandwidthUsed: receipt.ComputeBandwidthUsed,
+	})
+
+	if err := handler.backend.Put(key, usage.marshal()); err != nil {
+		return fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return nil
+}
+
+// GetResourceUsage returns the resources used by the transactions an account paid for between the
+// start height, inclusive, and the end height, exclusive. Usage is returned per bucket of
+// UsageBucketSize blocks, so the range is widened to whole buckets. Empty buckets are omitted.
+func (handler *TransactionStore) GetResourceUsage(account []byte, startHeight uint64, endHeight uint64) ([]*ResourceUsage, error) {
+	prefix := usagePrefix(account)
+	result := make([]*ResourceUsage, 0)
+
+	err := handler.backend.IterateFrom(prefix, usageKey(account, startHeight-startHeight%UsageBucketSize), false, func(key []byte, value []byte) error {
+		if len(key) != len(prefix)+8 {
+			return fmt.Errorf("%w, malformed resource usage key 0x%x", ErrDeserialization, key)
+		}
+		height := binary.BigEndian.Uint64(key[len(prefix):])
+		if height >= endHeight {
+			return errEndOfRange
+		}
+
+		usage, err := unmarshalResourceUsage(value)
+		if err != nil {
+			return err
+		}
+		usage.Height = height
+		result = append(result, usage)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errEndOfRange) {
+		if errors.Is(err, ErrDeserialization) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return result, nil
+}
+
+// GetResourceUsageByTime returns the resources used by the transactions an account paid for between
+// the start time, inclusive, and the end time, exclusive, in milliseconds. Times are mapped to
+// heights through the recorded block times, and usage is returned for every bucket overlapping the
+// range, as GetResourceUsage does.
+func (handler *TransactionStore) GetResourceUsageByTime(account []byte, startTime uint64, endTime uint64) ([]*ResourceUsage, error) {
+	// The range starts with the bucket of the last block at or before the start time
+	startHeight := uint64(0)
+	if startTime < math.MaxUint64 {
+		next, err := handler.HeightAt(startTime + 1)
+		if err != nil {
+			return nil, err
+		}
+		if next > 0 {
+			startHeight = next - 1
+		}
+	}
+
+	endHeight, err := handler.HeightAt(endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler.GetResourceUsage(account, startHeight, endHeight)
+}
@@ -0,0 +1,244 @@
+package trxstore
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+)
+
+func TestResourceUsage(t *testing.T) {
+	alice := []byte{0, 1}
+	bob := []byte{0, 2}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		add := func(id byte, payer []byte, height uint64, rcUsed uint64) {
+			receipt := &protocol.TransactionReceipt{
+				Id:                   []byte{id},
+				Payer:                payer,
+				RcUsed:               rcUsed,
+				DiskStorageUsed:      1,
+				NetworkBandwidthUsed: 2,
+				ComputeBandwidthUsed: 3,
+			}
+			topology := &koinos.BlockTopology{Id: []byte{id}, Height: height}
+			if err := store.AddIncludedTransactionWithReceipt(&protocol.Transaction{Id: []byte{id}}, receipt, topology); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+		}
+
+		add(1, alice, 1, 100)
+		add(2, alice, UsageBucketSize-1, 200)
+		add(3, bob, 5, 400)
+		add(4, alice, 2*UsageBucketSize+1, 800)
+
+		// Including a transaction again is not counted twice
+		add(1, alice, 2, 100)
+
+		// Transactions without receipts are not counted
+		if err := store.AddIncludedTransaction(&protocol.Transaction{Id: []byte{5}}, &koinos.BlockTopology{Id: []byte{5}, Height: 3}); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+
+		usage, err := store.GetResourceUsage(alice, 0, 3*UsageBucketSize)
+		if err != nil {
+			t.Fatal("Error getting resource usage: ", err)
+		}
+		if len(usage) != 2 {
+			t.Fatalf("Expected 2 buckets, got %v", len(usage))
+		}
+		expected := ResourceUsage{Height: 0, Transactions: 2, RcUsed: 300, DiskStorageUsed: 2, NetworkBandwidthUsed: 4, ComputeBandwidthUsed: 6}
+		if *usage[0] != expected {
+			t.Fatalf("Expected %+v, got %+v", expected, *usage[0])
+		}
+		expected = ResourceUsage{Height: 2 * UsageBucketSize, Transactions: 1, RcUsed: 800, DiskStorageUsed: 1, NetworkBandwidthUsed: 2, ComputeBandwidthUsed: 3}
+		if *usage[1] != expected {
+			t.Fatalf("Expected %+v, got %+v", expected, *usage[1])
+		}
+
+		// Usage is aggregated into one record per bucket
+		records := 0
+		if err := b.Iterate(usagePrefix(alice), func(key []byte, value []byte) error {
+			records++
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if records != 2 {
+			t.Fatalf("Expected 2 usage records, got %v", records)
+		}
+
+		// The range is widened to whole buckets
+		usage, err = store.GetResourceUsage(alice, UsageBucketSize/2, 2*UsageBucketSize)
+		if err != nil {
+			t.Fatal("Error getting resource usage: ", err)
+		}
+		if len(usage) != 1 || usage[0].Height != 0 {
+			t.Fatalf("Expected only the first bucket, got %v", usage)
+		}
+
+		usage, err = store.GetResourceUsage(bob, 0, 10)
+		if err != nil {
+			t.Fatal("Error getting resource usage: ", err)
+		}
+		if len(usage) != 1 || usage[0].RcUsed != 400 {
+			t.Fatalf("Unexpected usage %v", usage)
+		}
+
+		usage, err = store.GetResourceUsage([]byte{0}, 0, 10)
+		if err != nil || len(usage) != 0 {
+			t.Fatal("Expected no usage")
+		}
+
+		_, err = store.Verify(func(problem *VerificationProblem) {
+			t.Error("Unexpected problem: ", problem)
+		})
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+// failingItemBackend fails to store transaction records while fail is set
+type failingItemBackend struct {
+	TransactionStoreBackend
+	fail bool
+}
+
+func (backend *failingItemBackend) Put(key []byte, value []byte) error {
+	if backend.fail && !isAuxiliaryKey(key) {
+		return errors.New("error on put")
+	}
+	return backend.TransactionStoreBackend.Put(key, value)
+}
+
+func TestResourceUsageRetry(t *testing.T) {
+	payer := []byte{0, 1}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		failing := &failingItemBackend{TransactionStoreBackend: b, fail: true}
+		store := NewTransactionStore(failing)
+
+		trx := &protocol.Transaction{Id: []byte{1}}
+		receipt := &protocol.TransactionReceipt{Id: trx.Id, Payer: payer, RcUsed: 5}
+		topology := &koinos.BlockTopology{Id: []byte{1}, Height: 10}
+
+		// A transaction whose record could not be stored is added again in full, its usage is counted once
+		if err := store.AddIncludedTransactionWithReceipt(trx, receipt, topology); !errors.Is(err, ErrBackend) {
+			t.Fatal("Expected ErrBackend, got ", err)
+		}
+		failing.fail = false
+		if err := store.AddIncludedTransactionWithReceipt(trx, receipt, topology); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+		if err := store.AddIncludedTransactions([]*IncludedTransaction{{Transaction: trx, Receipt: receipt, Topology: topology}}); err != nil {
+			t.Fatal("Error adding transactions: ", err)
+		}
+
+		usage, err := store.GetResourceUsage(payer, 0, UsageBucketSize)
+		if err != nil {
+			t.Fatal("Error getting resource usage: ", err)
+		}
+		if len(usage) != 1 || usage[0].Transactions != 1 || usage[0].RcUsed != 5 {
+			t.Fatalf("Expected a single transaction using 5 rc, got %+v", usage)
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestResourceUsageByTime(t *testing.T) {
+	payer := []byte{0, 1}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		// Blocks are a second apart, one transaction is added in each of the first three buckets
+		for bucket := uint64(0); bucket < 3; bucket++ {
+			height := bucket*UsageBucketSize + 1
+			trx := &protocol.Transaction{Id: []byte{1, byte(bucket)}}
+			receipt := &protocol.TransactionReceipt{Id: trx.Id, Payer: payer, RcUsed: 1}
+			if err := store.AddIncludedTransactionWithReceipt(trx, receipt, &koinos.BlockTopology{Id: trx.Id, Height: height}); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+			if err := store.PutBlockTime(bucket*UsageBucketSize, bucket*UsageBucketSize*1000); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, test := range []struct {
+			start, end uint64
+			expected   []uint64
+		}{
+			{0, 3 * UsageBucketSize * 1000, []uint64{0, UsageBucketSize, 2 * UsageBucketSize}},
+			{UsageBucketSize * 1000, 2 * UsageBucketSize * 1000, []uint64{UsageBucketSize}},
+			{UsageBucketSize*1000 + 500, UsageBucketSize*1000 + 600, []uint64{UsageBucketSize}},
+			{2*UsageBucketSize*1000 + 1, 1 << 62, []uint64{2 * UsageBucketSize}},
+		} {
+			usage, err := store.GetResourceUsageByTime(payer, test.start, test.end)
+			if err != nil {
+				t.Fatal("Error getting resource usage: ", err)
+			}
+			if len(usage) != len(test.expected) {
+				t.Fatalf("Expected %v buckets between %v and %v, got %+v", len(test.expected), test.start, test.end, usage)
+			}
+			for i, height := range test.expected {
+				if usage[i].Height != height || usage[i].Transactions != 1 {
+					t.Fatalf("Expected one transaction in the bucket at %v, got %+v", height, usage[i])
+				}
+			}
+		}
+
+		CloseBackend(b)
+	}
+}
+
+func TestConcurrentResourceUsage(t *testing.T) {
+	payer := []byte{0, 1}
+
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		// Transactions of the same payer and bucket are added concurrently, one at a time and in batches
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				trx := &protocol.Transaction{Id: []byte{1, byte(i)}}
+				receipt := &protocol.TransactionReceipt{Id: trx.Id, Payer: payer, RcUsed: 1}
+				topology := &koinos.BlockTopology{Id: trx.Id, Height: uint64(i)}
+				var err error
+				if i%2 == 0 {
+					err = store.AddIncludedTransactionWithReceipt(trx, receipt, topology)
+				} else {
+					err = store.AddIncludedTransactions([]*IncludedTransaction{{Transaction: trx, Receipt: receipt, Topology: topology}})
+				}
+				if err != nil {
+					t.Error("Error adding transaction: ", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		usage, err := store.GetResourceUsage(payer, 0, UsageBucketSize)
+		if err != nil {
+			t.Fatal("Error getting resource usage: ", err)
+		}
+		if len(usage) != 1 || usage[0].Transactions != 20 || usage[0].RcUsed != 20 {
+			t.Fatalf("Expected 20 transactions using 20 rc, got %+v", usage)
+		}
+
+		CloseBackend(b)
+	}
+}
@@ -0,0 +1,272 @@
+package trxstore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// VerificationProblem describes an inconsistency found in a stored record
+type VerificationProblem struct {
+	Key    []byte
+	Reason string
+}
+
+func (p *VerificationProblem) String() string {
+	return fmt.Sprintf("0x%x: %s", p.Key, p.Reason)
+}
+
+// VerificationResult summarizes a database verification
+type VerificationResult struct {
+	Transactions     uint64
+	AuxiliaryRecords uint64
+	Problems         uint64
+}
+
+// Verify walks every stored record and checks that it is consistent. Each
+// problem found is passed to onProblem, verification continues after a
+// problem and only stops early if the backend fails. Transactions being added
+// while verification runs may be reported as partially indexed.
+func (handler *TransactionStore) Verify(onProblem func(problem *VerificationProblem)) (*VerificationResult, error) {
+	result := &VerificationResult{}
+	report := func(key []byte, format string, args ...interface{}) {
+		result.Problems++
+		onProblem(&VerificationProblem{Key: key, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	err := handler.backend.Iterate(nil, func(key []byte, value []byte) error {
+		if isAuxiliaryKey(key) {
+			result.AuxiliaryRecords++
+			reason, err := handler.verifyAuxiliaryRecord(key, value)
+			if err != nil {
+				return err
+			}
+			if len(reason) > 0 {
+				report(key, "%s", reason)
+			}
+			return nil
+		}
+
+		result.Transactions++
+
+		item := &transaction_store.TransactionItem{}
+		if err := unmarshalItem(value, item); err != nil {
+			report(key, "record is not a transaction item, %v", err)
+			return nil
+		}
+
+		if item.Transaction == nil {
+			report(key, "transaction item has no transaction")
+			return nil
+		}
+
+		if !bytes.Equal(key, item.Transaction.Id) {
+			report(key, "key does not match transaction id 0x%x", item.Transaction.Id)
+		}
+
+		if item.Transaction.Header != nil {
+			if err := checkTransactionID(item.Transaction); err != nil {
+				report(key, "%v", err)
+			}
+		}
+
+		blocks, err := handler.getContainingBlocks(key)
+		if err != nil {
+			return err
+		}
+
+		if len(item.ContainingBlocks) == 0 && len(blocks) == 0 {
+			report(key, "transaction item has no containing blocks")
+		}
+
+		// Blocks may be both in a record and stored separately while it is migrated, but not twice in either
+		for _, list := range [][][]byte{item.ContainingBlocks, blocks} {
+			seen := make(map[string]struct{}, len(list))
+			for _, blockID := range list {
+				if _, ok := seen[string(blockID)]; ok {
+					report(key, "duplicate containing block 0x%x", blockID)
+				}
+				seen[string(blockID)] = struct{}{}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("%w, %v", ErrBackend, err)
+	}
+
+	return result, nil
+}
+
+// verifyAuxiliaryRecord checks an auxiliary record against the primary records
+// it refers to, returning a description of the problem if it is inconsistent
+func (handler *TransactionStore) verifyAuxiliaryRecord(key []byte, value []byte) (string, error) {
+	if len(key) <= len(auxiliaryPrefix) {
+		return "unknown auxiliary record", nil
+	}
+
+	switch key[len(auxiliaryPrefix)] {
+	case indexRecordNamespace:
+		trxID := key[len(auxiliaryPrefix)+1:]
+		indexed, reason, err := handler.loadIndexed(trxID)
+		if err != nil || len(reason) > 0 {
+			return reason, err
+		}
+
+		for _, entry := range indexed.entries() {
+			entryValue, err := handler.backend.Get(entry)
+			if err != nil {
+				return "", err
+			}
+			if !bytes.Equal(entryValue, trxID) {
+				return fmt.Sprintf("missing index entry 0x%x", entry), nil
+			}
+		}
+
+	case receiptNamespace:
+		trxID := key[len(auxiliaryPrefix)+1:]
+		receipt, err := handler.getReceipt(trxID)
+		if errors.Is(err, ErrDeserialization) {
+			return "record is not a transaction receipt", nil
+		} else if err != nil {
+			return "", err
+		}
+		if !bytes.Equal(receipt.Id, trxID) {
+			return fmt.Sprintf("key does not match receipt id 0x%x", receipt.Id), nil
+		}
+
+		item, err := handler.getItem(trxID)
+		if err != nil && !errors.Is(err, ErrDeserialization) {
+			return "", err
+		}
+		if item == nil {
+			return fmt.Sprintf("receipt of 0x%x has no transaction", trxID), nil
+		}
+
+	case usageNamespace:
+		if _, err := unmarshalResourceUsage(value); err != nil {
+			return "record is not a resource usage record", nil
+		}
+
+	case outboxNamespace:
+		if len(key) != len(outboxKey(0)) {
+			return "malformed outbox entry key", nil
+		}
+		if _, err := unmarshalOutboxEntry(value); err != nil {
+			return "record is not an outbox entry", nil
+		}
+
+	case containingBlockNamespace:
+		length, n := protowire.ConsumeVarint(key[len(auxiliaryPrefix)+1:])
+		if n < 0 || len(key) != len(auxiliaryPrefix)+1+n+int(length)+4 {
+			return "malformed containing block key", nil
+		}
+		trxID := key[len(auxiliaryPrefix)+1+n : len(key)-4]
+		if len(value) == 0 {
+			return "containing block record has no block id", nil
+		}
+
+		record, err := handler.getRecord(trxID)
+		if err != nil && !errors.Is(err, ErrDeserialization) {
+			return "", err
+		}
+		if record == nil {
+			return fmt.Sprintf("containing block of 0x%x has no transaction", trxID), nil
+		}
+
+	case metadataNamespace:
+		switch string(key[len(auxiliaryPrefix)+1:]) {
+		case schemaVersionName:
+			if len(value) != 8 {
+				return "malformed schema version", nil
+			}
+		case migrationProgressName:
+			if len(value) < 8 {
+				return "malformed migration progress", nil
+			}
+		default:
+			return "unknown metadata record", nil
+		}
+
+	case blockTimeIndexNamespace:
+		if _, _, err := parseBlockTime(key, value); err != nil {
+			return "record is not a block time", nil
+		}
+
+	case signerIndexNamespace, contractCallIndexNamespace, operationTypeIndexNamespace, eventIndexNamespace, nonceIndexNamespace, heightIndexNamespace:
+		indexed, reason, err := handler.loadIndexed(value)
+		if err != nil || len(reason) > 0 {
+			return reason, err
+		}
+
+		for _, entry := range indexed.entries() {
+			if bytes.Equal(entry, key) {
+				return "", nil
+			}
+		}
+
+		// Height index entries superseded by a later inclusion remain until they are pruned
+		if key[len(auxiliaryPrefix)] == heightIndexNamespace && len(key) == len(heightIndexKey(0, value)) {
+			height := binary.BigEndian.Uint64(key[len(key)-len(value)-8:])
+			if height >= indexed.record.height && height < indexed.record.retainedHeight() {
+				return "", nil
+			}
+		}
+
+		return fmt.Sprintf("index entry does not match transaction 0x%x", value), nil
+
+	default:
+		return "unknown auxiliary record", nil
+	}
+
+	return "", nil
+}
+
+// indexedTransaction is a transaction with everything its index entries are derived from
+type indexedTransaction struct {
+	item    *transaction_store.TransactionItem
+	record  *indexRecord
+	receipt *protocol.TransactionReceipt
+}
+
+func (indexed *indexedTransaction) entries() [][]byte {
+	return indexEntries(indexed.item.Transaction, indexed.record, indexed.receipt)
+}
+
+// loadIndexed loads an indexed transaction for verification
+func (handler *TransactionStore) loadIndexed(trxID []byte) (*indexedTransaction, string, error) {
+	item, err := handler.getItem(trxID)
+	if errors.Is(err, ErrDeserialization) {
+		return nil, fmt.Sprintf("indexed transaction 0x%x is not a transaction item", trxID), nil
+	} else if err != nil {
+		return nil, "", err
+	}
+	if item == nil || item.Transaction == nil {
+		return nil, fmt.Sprintf("indexed transaction 0x%x does not exist", trxID), nil
+	}
+
+	record, err := handler.getIndexRecord(trxID)
+	if errors.Is(err, ErrDeserialization) {
+		return nil, fmt.Sprintf("index record of 0x%x is malformed", trxID), nil
+	} else if err != nil {
+		return nil, "", err
+	}
+	if record == nil {
+		return nil, fmt.Sprintf("indexed transaction 0x%x has no index record", trxID), nil
+	}
+
+	receipt, err := handler.getReceipt(trxID)
+	if errors.Is(err, ErrDeserialization) {
+		return nil, fmt.Sprintf("receipt of 0x%x is malformed", trxID), nil
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	return &indexedTransaction{item: item, record: record, receipt: receipt}, "", nil
+}
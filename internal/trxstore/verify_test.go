@@ -0,0 +1,81 @@
+package trxstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-proto-golang/v2/koinos/transaction_store"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestVerify(t *testing.T) {
+	for bType := range backendTypes {
+		b := NewBackend(bType)
+		store := NewTransactionStore(b)
+
+		for i := byte(1); i <= 3; i++ {
+			trx := &protocol.Transaction{Id: []byte{i}}
+			if err := store.AddIncludedTransaction(trx, &koinos.BlockTopology{Id: []byte{i}}); err != nil {
+				t.Fatal("Error adding transaction: ", err)
+			}
+		}
+
+		problems := make([]*VerificationProblem, 0)
+		onProblem := func(problem *VerificationProblem) {
+			problems = append(problems, problem)
+		}
+
+		result, err := store.Verify(onProblem)
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+		if result.Transactions != 3 || result.Problems != 0 || len(problems) != 0 {
+			t.Fatal("Expected a consistent database, got: ", problems)
+		}
+
+		// Corrupt the database
+		if err = b.Put([]byte{4}, []byte{0, 0, 255, 255, 255, 255, 255}); err != nil {
+			t.Fatal(err)
+		}
+
+		item := &transaction_store.TransactionItem{
+			Transaction:      &protocol.Transaction{Id: []byte{1}},
+			ContainingBlocks: [][]byte{{1}, {1}},
+		}
+		itemBytes, err := proto.Marshal(item)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = b.Put([]byte{5}, itemBytes); err != nil {
+			t.Fatal(err)
+		}
+
+		forged := &protocol.Transaction{Id: []byte{6}, Header: &protocol.TransactionHeader{RcLimit: 10}}
+		if err = store.AddIncludedTransaction(forged, &koinos.BlockTopology{Id: []byte{6}}); err != nil {
+			t.Fatal("Error adding transaction: ", err)
+		}
+
+		result, err = store.Verify(onProblem)
+		if err != nil {
+			t.Fatal("Error verifying database: ", err)
+		}
+		if result.Transactions != 6 {
+			t.Fatalf("Expected 6 verified transactions, got %v", result.Transactions)
+		}
+		if result.Problems != 4 || len(problems) != 4 {
+			t.Fatalf("Expected 4 problems, got: %v", problems)
+		}
+
+		CloseBackend(b)
+	}
+
+	// Test error backend
+	{
+		store := NewTransactionStore(&ErrorBackend{})
+		if _, err := store.Verify(func(*VerificationProblem) {}); !errors.Is(err, ErrBackend) {
+			t.Fatal("Got unexpected error verifying database: ", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/koinos/koinos-transaction-store/internal/notify"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrInvalidConfig occurs when the webhook configuration cannot be parsed
+var ErrInvalidConfig = errors.New("invalid webhook config")
+
+// Webhook is a URL notified of the inclusion, reorganization and
+// irreversibility of transactions involving any of its addresses or contracts
+type Webhook struct {
+	URL string
+
+	// Secret is the key payloads are signed with
+	Secret string
+
+	Addresses [][]byte
+	Contracts [][]byte
+
+	// Types are the notification types delivered, every type if empty
+	Types []notify.NotificationType
+}
+
+// notifiedOf returns true if notifications of the given type are delivered to the webhook
+func (webhook *Webhook) notifiedOf(notificationType notify.NotificationType) bool {
+	if len(webhook.Types) == 0 {
+		return true
+	}
+
+	for _, t := range webhook.Types {
+		if t == notificationType {
+			return true
+		}
+	}
+
+	return false
+}
+
+type webhookConfig struct {
+	URL       string   `yaml:"url"`
+	Secret    string   `yaml:"secret"`
+	Addresses []string `yaml:"addresses"`
+	Contracts []string `yaml:"contracts"`
+	Types     []string `yaml:"types"`
+}
+
+// ParseConfig parses the webhooks section of the configuration, a list of
+// webhooks with a url, a secret, base58 encoded addresses and contracts, and
+// optionally the notification types to deliver, every type by default:
+//
+//	webhooks:
+//	  - url: https://example.com/deposits
+//	    secret: a shared secret
+//	    addresses: [1AddressToWatch]
+//	    contracts: [1ContractToWatch]
+//	    types: [irreversible]
+//
+// The service only notifies about the blocks it accepts once it is live, not
+// about those replayed while syncing, unless notify-sync-blocks is set.
+func ParseConfig(config interface{}) ([]*Webhook, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	// The section has already been decoded into generic values, so it is re-encoded and decoded into its structure
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrInvalidConfig, err)
+	}
+
+	configs := make([]*webhookConfig, 0)
+	if err := yaml.UnmarshalStrict(data, &configs); err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrInvalidConfig, err)
+	}
+
+	webhooks := make([]*Webhook, len(configs))
+	for i, c := range configs {
+		if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("%w, webhook %v has an invalid url '%s'", ErrInvalidConfig, i, c.URL)
+		}
+		if len(c.Secret) == 0 {
+			return nil, fmt.Errorf("%w, webhook %v has no secret", ErrInvalidConfig, i)
+		}
+
+		webhooks[i] = &Webhook{URL: c.URL, Secret: c.Secret}
+		if webhooks[i].Addresses, err = decodeBase58(c.Addresses); err != nil {
+			return nil, fmt.Errorf("%w, webhook %v has an invalid address, %v", ErrInvalidConfig, i, err)
+		}
+		if webhooks[i].Contracts, err = decodeBase58(c.Contracts); err != nil {
+			return nil, fmt.Errorf("%w, webhook %v has an invalid contract, %v", ErrInvalidConfig, i, err)
+		}
+		for _, t := range c.Types {
+			switch notificationType := notify.NotificationType(t); notificationType {
+			case notify.Included, notify.Reorged, notify.Irreversible:
+				webhooks[i].Types = append(webhooks[i].Types, notificationType)
+			default:
+				return nil, fmt.Errorf("%w, webhook %v has an unknown notification type '%s'", ErrInvalidConfig, i, t)
+			}
+		}
+	}
+
+	return webhooks, nil
+}
+
+func decodeBase58(values []string) ([][]byte, error) {
+	decoded := make([][]byte, len(values))
+	for i, value := range values {
+		decoded[i] = base58.Decode(value)
+		if len(decoded[i]) == 0 {
+			return nil, fmt.Errorf("'%s' is not base58", value)
+		}
+	}
+
+	return decoded, nil
+}
@@ -0,0 +1,320 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/koinos/koinos-log-golang/v2"
+	"github.com/koinos/koinos-transaction-store/internal/notify"
+	"github.com/koinos/koinos-transaction-store/internal/query"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+// Delivery headers
+const (
+	// SignatureHeader holds the hex encoded HMAC-SHA256, keyed by the webhook secret, of the timestamp
+	// header, a period and the payload, prefixed by "sha256="
+	SignatureHeader = "X-Koinos-Signature"
+
+	// TimestampHeader holds the time of the attempt in seconds since the Unix epoch. It is signed with
+	// the payload so that receivers can refuse stale deliveries replayed by an attacker.
+	TimestampHeader = "X-Koinos-Timestamp"
+
+	// DeliveryHeader holds the ID of the delivery, which is the same for every attempt
+	DeliveryHeader = "X-Koinos-Delivery"
+)
+
+const (
+	defaultRetryDelay    = time.Second
+	defaultMaxRetryDelay = time.Hour
+	defaultMaxAttempts   = 20
+	defaultTimeout       = 10 * time.Second
+
+	// outboxBatchSize is the number of outbox entries read at once
+	outboxBatchSize = 100
+
+	// idleInterval is the longest the dispatcher waits before checking the outbox again
+	idleInterval = time.Minute
+)
+
+// Payload is the JSON body POSTed to a webhook
+type Payload struct {
+	Type          notify.NotificationType `json:"type"`
+	TransactionID query.HexBytes          `json:"transaction_id"`
+	BlockID       query.HexBytes          `json:"block_id"`
+	Height        string                  `json:"height"`
+	Transaction   json.RawMessage         `json:"transaction"`
+}
+
+// Option configures optional Dispatcher behavior
+type Option func(*Dispatcher)
+
+// WithRetryDelay sets the delay before the first retry, doubled on every following retry up to maxDelay
+func WithRetryDelay(delay time.Duration, maxDelay time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.retryDelay = delay
+		d.maxRetryDelay = maxDelay
+	}
+}
+
+// WithMaxAttempts sets the number of attempts after which a delivery is abandoned
+func WithMaxAttempts(attempts uint32) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = attempts
+	}
+}
+
+// WithHTTPClient sets the client deliveries are made with
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// Dispatcher delivers notifications about transactions to webhooks.
+// Deliveries are queued in the store's outbox before they are attempted so
+// that they survive restarts.
+type Dispatcher struct {
+	store    *trxstore.TransactionStore
+	webhooks map[string]*Webhook
+	ordered  []*Webhook
+	client   *http.Client
+	wake     chan struct{}
+
+	retryDelay    time.Duration
+	maxRetryDelay time.Duration
+	maxAttempts   uint32
+}
+
+// NewDispatcher creates a Dispatcher delivering to the given webhooks
+func NewDispatcher(store *trxstore.TransactionStore, webhooks []*Webhook, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:         store,
+		webhooks:      make(map[string]*Webhook, len(webhooks)),
+		ordered:       webhooks,
+		client:        &http.Client{Timeout: defaultTimeout},
+		wake:          make(chan struct{}, 1),
+		retryDelay:    defaultRetryDelay,
+		maxRetryDelay: defaultMaxRetryDelay,
+		maxAttempts:   defaultMaxAttempts,
+	}
+	for _, webhook := range webhooks {
+		d.webhooks[webhook.URL] = webhook
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Notify is a notify.Listener that queues a delivery to every webhook the notification matches
+func (d *Dispatcher) Notify(n *notify.Notification) {
+	var entries []*trxstore.OutboxEntry
+	var payload []byte
+
+	for _, webhook := range d.ordered {
+		if !webhook.matches(n) {
+			continue
+		}
+
+		if payload == nil {
+			var err error
+			if payload, err = makePayload(n); err != nil {
+				log.Warnf("Could not create webhook payload for transaction 0x%x: %s", n.TransactionID, err.Error())
+				return
+			}
+		}
+
+		entries = append(entries, &trxstore.OutboxEntry{Destination: webhook.URL, Payload: payload})
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := d.store.AddOutboxEntries(entries); err != nil {
+		log.Errorf("Could not queue webhook deliveries for transaction 0x%x: %s", n.TransactionID, err.Error())
+		return
+	}
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers queued notifications until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		wait := d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// deliverDue attempts every delivery that is due and returns how long to wait for the next one
+func (d *Dispatcher) deliverDue(ctx context.Context) time.Duration {
+	wait := idleInterval
+	var afterID uint64
+
+	for {
+		entries, err := d.store.GetOutboxEntries(afterID, outboxBatchSize)
+		if err != nil {
+			log.Errorf("Could not read webhook outbox: %s", err.Error())
+			return wait
+		}
+
+		for _, entry := range entries {
+			afterID = entry.ID
+			if ctx.Err() != nil {
+				return wait
+			}
+
+			if untilDue := time.Until(time.Unix(0, entry.NextAttempt*int64(time.Millisecond))); untilDue > 0 {
+				if untilDue < wait {
+					wait = untilDue
+				}
+				continue
+			}
+
+			if next := d.attempt(ctx, entry); next > 0 && next < wait {
+				wait = next
+			}
+		}
+
+		if len(entries) < outboxBatchSize {
+			return wait
+		}
+	}
+}
+
+// attempt delivers an entry, returning the delay before it is retried or 0 if it is done
+func (d *Dispatcher) attempt(ctx context.Context, entry *trxstore.OutboxEntry) time.Duration {
+	webhook, ok := d.webhooks[entry.Destination]
+	if !ok {
+		log.Warnf("Dropping delivery %v to %s, which is no longer configured", entry.ID, entry.Destination)
+		d.remove(entry)
+		return 0
+	}
+
+	err := d.deliver(ctx, webhook, entry)
+	if err == nil {
+		d.remove(entry)
+		return 0
+	}
+
+	entry.Attempts++
+	if entry.Attempts >= d.maxAttempts {
+		log.Errorf("Abandoning delivery %v to %s after %v attempts: %s", entry.ID, entry.Destination, entry.Attempts, err.Error())
+		d.remove(entry)
+		return 0
+	}
+
+	delay := d.retryDelay << (entry.Attempts - 1)
+	if delay > d.maxRetryDelay || delay <= 0 {
+		delay = d.maxRetryDelay
+	}
+	entry.NextAttempt = time.Now().Add(delay).UnixNano() / int64(time.Millisecond)
+
+	log.Warnf("Delivery %v to %s failed, retrying in %v: %s", entry.ID, entry.Destination, delay, err.Error())
+	if err := d.store.UpdateOutboxEntry(entry); err != nil {
+		log.Errorf("Could not update webhook outbox: %s", err.Error())
+	}
+
+	return delay
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, webhook *Webhook, entry *trxstore.OutboxEntry) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(entry.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, entry.Payload))
+	request.Header.Set(DeliveryHeader, strconv.FormatUint(entry.ID, 10))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", response.Status)
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) remove(entry *trxstore.OutboxEntry) {
+	if err := d.store.RemoveOutboxEntry(entry.ID); err != nil {
+		log.Errorf("Could not update webhook outbox: %s", err.Error())
+	}
+}
+
+// Sign returns the value of the signature header of a payload delivered at the given timestamp
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// matches returns true if the webhook is notified of the notification's type and the transaction
+// involves one of its addresses, including the addresses impacted by its events, or contracts
+func (webhook *Webhook) matches(n *notify.Notification) bool {
+	if !webhook.notifiedOf(n.Type) {
+		return false
+	}
+
+	for _, address := range webhook.Addresses {
+		for _, a := range n.Addresses {
+			if bytes.Equal(address, a) {
+				return true
+			}
+		}
+	}
+
+	for _, contract := range webhook.Contracts {
+		for _, op := range n.Transaction.GetOperations() {
+			if bytes.Equal(contract, op.GetCallContract().GetContractId()) || bytes.Equal(contract, op.GetUploadContract().GetContractId()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func makePayload(n *notify.Notification) ([]byte, error) {
+	transaction, err := query.MarshalMessage(n.Transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&Payload{
+		Type:          n.Type,
+		TransactionID: n.TransactionID,
+		BlockID:       n.BlockID,
+		Height:        strconv.FormatUint(n.Height, 10),
+		Transaction:   transaction,
+	})
+}
@@ -0,0 +1,253 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koinos/koinos-proto-golang/v2/koinos/protocol"
+	"github.com/koinos/koinos-transaction-store/internal/notify"
+	"github.com/koinos/koinos-transaction-store/internal/trxstore"
+)
+
+type delivery struct {
+	signature string
+	id        string
+	payload   *Payload
+}
+
+// stubServer records deliveries, failing the first failures requests
+func stubServer(t *testing.T, secret string, failures int) (*httptest.Server, chan *delivery) {
+	deliveries := make(chan *delivery, 16)
+	var mutex sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if err != nil || time.Since(time.Unix(timestamp, 0)) > time.Minute {
+			t.Errorf("Unexpected timestamp %s", r.Header.Get(TimestampHeader))
+		}
+		if r.Header.Get(SignatureHeader) != Sign(secret, r.Header.Get(TimestampHeader), body) {
+			t.Errorf("Unexpected signature %s", r.Header.Get(SignatureHeader))
+		}
+
+		if failures > 0 {
+			failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		payload := &Payload{}
+		if err := json.Unmarshal(body, payload); err != nil {
+			t.Errorf("Could not parse payload: %s", err.Error())
+		}
+		deliveries <- &delivery{signature: r.Header.Get(SignatureHeader), id: r.Header.Get(DeliveryHeader), payload: payload}
+	}))
+
+	return server, deliveries
+}
+
+func makeNotification(id byte, payer byte, contract byte) *notify.Notification {
+	tx := &protocol.Transaction{
+		Id:     []byte{id},
+		Header: &protocol.TransactionHeader{Payer: []byte{payer}},
+	}
+	if contract != 0 {
+		tx.Operations = []*protocol.Operation{{Op: &protocol.Operation_CallContract{CallContract: &protocol.CallContractOperation{ContractId: []byte{contract}}}}}
+	}
+
+	return &notify.Notification{
+		Type:          notify.Included,
+		TransactionID: tx.Id,
+		BlockID:       []byte{0xb1},
+		Height:        7,
+		Transaction:   tx,
+		Addresses:     [][]byte{{payer}},
+	}
+}
+
+func expectDelivery(t *testing.T, deliveries chan *delivery, id byte, notificationType notify.NotificationType) *delivery {
+	select {
+	case d := <-deliveries:
+		if len(d.payload.TransactionID) != 1 || d.payload.TransactionID[0] != id {
+			t.Fatalf("Expected a delivery of transaction %v, got %v", id, d.payload.TransactionID)
+		}
+		if d.payload.Height != "7" || d.payload.Type != notificationType {
+			t.Fatalf("Unexpected payload %+v", d.payload)
+		}
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a delivery")
+	}
+	return nil
+}
+
+func expectOutbox(t *testing.T, store *trxstore.TransactionStore, count int) {
+	entries, err := store.GetOutboxEntries(0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != count {
+		t.Fatalf("Expected %v outbox entries, got %v", count, len(entries))
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	server, deliveries := stubServer(t, "secret", 2)
+	defer server.Close()
+
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+	webhooks := []*Webhook{{URL: server.URL, Secret: "secret", Addresses: [][]byte{{1}}, Contracts: [][]byte{{9}}}}
+	dispatcher := NewDispatcher(store, webhooks, WithRetryDelay(time.Millisecond, 10*time.Millisecond))
+
+	// Only notifications of transactions matching an address or contract are queued, whatever their type
+	dispatcher.Notify(makeNotification(10, 1, 0))
+	dispatcher.Notify(makeNotification(11, 2, 9))
+	dispatcher.Notify(makeNotification(12, 2, 8))
+	reorged := makeNotification(13, 1, 0)
+	reorged.Type = notify.Reorged
+	dispatcher.Notify(reorged)
+
+	// A deposit matches through the addresses impacted by its events
+	deposit := makeNotification(14, 2, 0)
+	deposit.Type = notify.Irreversible
+	deposit.Addresses = append(deposit.Addresses, []byte{1})
+	dispatcher.Notify(deposit)
+	expectOutbox(t, store, 4)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+
+	// Failed deliveries are retried, after those that did not fail
+	delivered := make(map[byte]*delivery)
+	for i := 0; i < 4; i++ {
+		select {
+		case d := <-deliveries:
+			delivered[d.payload.TransactionID[0]] = d
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for a delivery")
+		}
+	}
+	for id, notificationType := range map[byte]notify.NotificationType{10: notify.Included, 11: notify.Included, 13: notify.Reorged, 14: notify.Irreversible} {
+		if d, ok := delivered[id]; !ok || d.payload.Type != notificationType {
+			t.Fatalf("Expected a %s delivery of transaction %v, got %+v", notificationType, id, d)
+		}
+	}
+	first := delivered[10]
+
+	// Delivered entries are removed once the response has been read
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if entries, _ := store.GetOutboxEntries(0, 100); len(entries) == 0 {
+			break
+		}
+	}
+	expectOutbox(t, store, 0)
+
+	cancel()
+	<-done
+
+	if first.id != "1" {
+		t.Fatalf("Expected delivery ID 1, got %s", first.id)
+	}
+}
+
+func TestDispatcherPersistence(t *testing.T) {
+	server, deliveries := stubServer(t, "secret", 0)
+	defer server.Close()
+
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+	webhooks := []*Webhook{{URL: server.URL, Secret: "secret", Addresses: [][]byte{{1}}}}
+
+	// Deliveries queued before a restart are delivered by the new dispatcher
+	NewDispatcher(store, webhooks).Notify(makeNotification(10, 1, 0))
+	expectOutbox(t, store, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewDispatcher(store, webhooks).Run(ctx)
+
+	expectDelivery(t, deliveries, 10, notify.Included)
+}
+
+func TestDispatcherTypes(t *testing.T) {
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+	webhooks := []*Webhook{{URL: "https://example.com", Secret: "secret", Addresses: [][]byte{{1}}, Types: []notify.NotificationType{notify.Irreversible}}}
+	dispatcher := NewDispatcher(store, webhooks)
+
+	// Only the configured types are delivered
+	dispatcher.Notify(makeNotification(10, 1, 0))
+	expectOutbox(t, store, 0)
+
+	irreversible := makeNotification(10, 1, 0)
+	irreversible.Type = notify.Irreversible
+	dispatcher.Notify(irreversible)
+	expectOutbox(t, store, 1)
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	server, _ := stubServer(t, "secret", 100)
+	defer server.Close()
+
+	store := trxstore.NewTransactionStore(trxstore.NewMapBackend())
+	webhooks := []*Webhook{{URL: server.URL, Secret: "secret", Addresses: [][]byte{{1}}}}
+	dispatcher := NewDispatcher(store, webhooks, WithRetryDelay(time.Millisecond, time.Millisecond), WithMaxAttempts(3))
+	dispatcher.Notify(makeNotification(10, 1, 0))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		expectOutbox(t, store, 1)
+		time.Sleep(2 * time.Millisecond)
+		dispatcher.deliverDue(ctx)
+	}
+	expectOutbox(t, store, 0)
+
+	// Deliveries to webhooks that are no longer configured are dropped
+	dispatcher.Notify(makeNotification(11, 1, 0))
+	NewDispatcher(store, nil).deliverDue(ctx)
+	expectOutbox(t, store, 0)
+}
+
+func TestParseConfig(t *testing.T) {
+	config := []interface{}{
+		map[interface{}]interface{}{
+			"url":       "https://example.com/hook",
+			"secret":    "secret",
+			"addresses": []interface{}{"1AsdVfU7J8FzZKstyyQBVP2T8ppBk7yHvi"},
+			"types":     []interface{}{"reorged", "irreversible"},
+		},
+	}
+
+	webhooks, err := ParseConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(webhooks) != 1 || webhooks[0].URL != "https://example.com/hook" || len(webhooks[0].Addresses) != 1 || len(webhooks[0].Addresses[0]) != 25 || len(webhooks[0].Types) != 2 {
+		t.Fatalf("Unexpected webhooks %+v", webhooks)
+	}
+
+	for _, invalid := range []interface{}{
+		[]interface{}{map[interface{}]interface{}{"url": "ftp://example.com", "secret": "secret"}},
+		[]interface{}{map[interface{}]interface{}{"url": "https://example.com"}},
+		[]interface{}{map[interface{}]interface{}{"url": "https://example.com", "secret": "secret", "contracts": []interface{}{"0OIl"}}},
+		[]interface{}{map[interface{}]interface{}{"url": "https://example.com", "secret": "secret", "unknown": true}},
+		[]interface{}{map[interface{}]interface{}{"url": "https://example.com", "secret": "secret", "types": []interface{}{"accepted"}}},
+		"not a list",
+	} {
+		if _, err := ParseConfig(invalid); err == nil {
+			t.Fatalf("Expected an error parsing %v", invalid)
+		}
+	}
+}